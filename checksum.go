@@ -0,0 +1,117 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+)
+
+// ChecksumUpdater is an optional interface for Targets that allow replacing the checksum recorded for a migration.
+type ChecksumUpdater interface {
+	// UpdateChecksum replaces the checksum recorded for the migration with the given ID.
+	UpdateChecksum(ctx context.Context, id string, checksum string) error
+}
+
+// verifyChecksums checks that every applied migration still matches the checksum recorded when it was applied. If any
+// of them does not, a ChecksumMismatchError listing all of them is returned.
+func verifyChecksums(ctx context.Context, repo Repository, target Target) error {
+	drifted, err := findDriftedMigrations(ctx, repo, target)
+	if err != nil {
+		return err
+	}
+	if len(drifted) > 0 {
+		return newChecksumMismatchError(drifted...)
+	}
+	return nil
+}
+
+// findDriftedMigrations lists the applied migrations whose content does not match the checksum recorded when they were
+// applied. Migrations recorded without a checksum, or that do not implement ChecksummedMigration, are not verified.
+func findDriftedMigrations(ctx context.Context, repo Repository, target Target) ([]Migration, error) {
+	historyTarget, ok := target.(HistoryTarget)
+	if !ok {
+		return nil, ErrChecksumNotSupported
+	}
+
+	history, err := historyTarget.History(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed listing migrations applied: %w", err)
+	}
+
+	drifted := make([]Migration, 0)
+	for _, entry := range history {
+		if entry.Checksum == "" {
+			continue
+		}
+		m, err := repo.ByID(entry.ID)
+		if err != nil {
+			return nil, err
+		}
+		checksummed, ok := m.(ChecksummedMigration)
+		if !ok {
+			continue
+		}
+		if checksummed.Checksum() != entry.Checksum {
+			drifted = append(drifted, m)
+		}
+	}
+	return drifted, nil
+}
+
+// RepairChecksums accepts the current content of applied migrations that were changed after being applied, replacing
+// their recorded checksums. If no IDs are given, all drifted migrations are repaired. Otherwise, only the drifted
+// migrations with the given IDs are.
+//
+// The repair runs while the Target is locked, and the repaired migrations are returned.
+func RepairChecksums(ctx context.Context, source Source, target Target, ids ...string) ([]Migration, error) {
+	updater, ok := target.(ChecksumUpdater)
+	if !ok {
+		return nil, ErrChecksumNotSupported
+	}
+
+	unlocker, err := target.Lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = unlocker.Unlock(detach(ctx))
+	}()
+
+	repo, err := source.Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error listing available migrations", err)
+	}
+
+	for _, id := range ids {
+		_, err := repo.ByID(id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	drifted, err := findDriftedMigrations(ctx, repo, target)
+	if err != nil {
+		return nil, err
+	}
+
+	repaired := make([]Migration, 0, len(drifted))
+	for _, m := range drifted {
+		if len(ids) > 0 && !containsID(ids, m.ID()) {
+			continue
+		}
+		err := updater.UpdateChecksum(ctx, m.ID(), m.(ChecksummedMigration).Checksum())
+		if err != nil {
+			return repaired, WrapMigration(err, m)
+		}
+		repaired = append(repaired, m)
+	}
+	return repaired, nil
+}
+
+func containsID(ids []string, id string) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
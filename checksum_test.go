@@ -0,0 +1,165 @@
+package migrations
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// checksumTarget decorates the MockTarget with the HistoryTarget and ChecksumUpdater implementations.
+type checksumTarget struct {
+	*MockTarget
+	history []HistoryEntry
+	updated map[string]string
+}
+
+func (t *checksumTarget) History(_ context.Context) ([]HistoryEntry, error) {
+	return t.history, nil
+}
+
+func (t *checksumTarget) UpdateChecksum(_ context.Context, id string, checksum string) error {
+	if t.updated == nil {
+		t.updated = make(map[string]string)
+	}
+	t.updated[id] = checksum
+	return nil
+}
+
+type noopUnlocker struct{}
+
+func (noopUnlocker) Unlock(_ context.Context) error {
+	return nil
+}
+
+func Test_migratePlanner_Plan_VerifyChecksums(t *testing.T) {
+	t.Run("should fail listing the migrations with changed content", func(t *testing.T) {
+		ctx := context.Background()
+		ctrl := gomock.NewController(t)
+
+		m1 := &checksummedMigration{newMockMigration(ctrl, "1")}
+		m2 := &checksummedMigration{newMockMigration(ctrl, "2")}
+		m3 := &checksummedMigration{newMockMigration(ctrl, "3")}
+
+		source := NewMockSource(ctrl)
+		source.EXPECT().
+			Load(ctx).
+			Return(RepositoryBuilder().WithMigration(m1, m2, m3).Build(), nil)
+
+		target := &checksumTarget{
+			MockTarget: NewMockTarget(ctrl),
+			history: []HistoryEntry{
+				{ID: "1", Checksum: "changed"},
+				{ID: "2"}, // recorded without checksum, should not be verified.
+				{ID: "3", Checksum: "checksum 3"},
+			},
+		}
+		target.EXPECT().Current(ctx).Return(m3.ID(), nil)
+		target.EXPECT().Done(ctx).Return([]string{m1.ID(), m2.ID(), m3.ID()}, nil)
+
+		gotPlan, err := MigratePlannerWithOptions(VerifyChecksums())(source, target).Plan(ctx)
+		require.ErrorIs(t, err, ErrChecksumMismatch)
+		assert.Empty(t, gotPlan)
+
+		var mErr *ChecksumMismatchError
+		require.ErrorAs(t, err, &mErr)
+		assert.Equal(t, []Migration{m1}, mErr.Migrations())
+		assert.Equal(t, "migration content changed after being applied: migration 1", err.Error())
+	})
+
+	t.Run("should fail when the target does not record checksums", func(t *testing.T) {
+		ctx := context.Background()
+		ctrl := gomock.NewController(t)
+
+		m1 := newMockMigration(ctrl, "1")
+
+		source := NewMockSource(ctrl)
+		source.EXPECT().
+			Load(ctx).
+			Return(RepositoryBuilder().WithMigration(m1).Build(), nil)
+
+		target := NewMockTarget(ctrl)
+		target.EXPECT().Current(ctx).Return(m1.ID(), nil)
+		target.EXPECT().Done(ctx).Return([]string{m1.ID()}, nil)
+
+		_, err := MigratePlannerWithOptions(VerifyChecksums())(source, target).Plan(ctx)
+		require.ErrorIs(t, err, ErrChecksumNotSupported)
+	})
+}
+
+func TestRepairChecksums(t *testing.T) {
+	newTarget := func(ctrl *gomock.Controller) *checksumTarget {
+		target := &checksumTarget{
+			MockTarget: NewMockTarget(ctrl),
+			history: []HistoryEntry{
+				{ID: "1", Checksum: "changed"},
+				{ID: "2", Checksum: "changed"},
+				{ID: "3", Checksum: "checksum 3"},
+			},
+		}
+		target.EXPECT().Lock(gomock.Any()).Return(noopUnlocker{}, nil)
+		return target
+	}
+
+	t.Run("should repair all drifted migrations", func(t *testing.T) {
+		ctx := context.Background()
+		ctrl := gomock.NewController(t)
+
+		m1 := &checksummedMigration{newMockMigration(ctrl, "1")}
+		m2 := &checksummedMigration{newMockMigration(ctrl, "2")}
+		m3 := &checksummedMigration{newMockMigration(ctrl, "3")}
+
+		source := NewMockSource(ctrl)
+		source.EXPECT().
+			Load(ctx).
+			Return(RepositoryBuilder().WithMigration(m1, m2, m3).Build(), nil)
+
+		target := newTarget(ctrl)
+
+		repaired, err := RepairChecksums(ctx, source, target)
+		require.NoError(t, err)
+		assert.Equal(t, []Migration{m1, m2}, repaired)
+		assert.Equal(t, map[string]string{"1": "checksum 1", "2": "checksum 2"}, target.updated)
+	})
+
+	t.Run("should repair only the given migrations", func(t *testing.T) {
+		ctx := context.Background()
+		ctrl := gomock.NewController(t)
+
+		m1 := &checksummedMigration{newMockMigration(ctrl, "1")}
+		m2 := &checksummedMigration{newMockMigration(ctrl, "2")}
+		m3 := &checksummedMigration{newMockMigration(ctrl, "3")}
+
+		source := NewMockSource(ctrl)
+		source.EXPECT().
+			Load(ctx).
+			Return(RepositoryBuilder().WithMigration(m1, m2, m3).Build(), nil)
+
+		target := newTarget(ctrl)
+
+		repaired, err := RepairChecksums(ctx, source, target, m2.ID())
+		require.NoError(t, err)
+		assert.Equal(t, []Migration{m2}, repaired)
+		assert.Equal(t, map[string]string{"2": "checksum 2"}, target.updated)
+	})
+
+	t.Run("should fail when the given migration does not exist", func(t *testing.T) {
+		ctx := context.Background()
+		ctrl := gomock.NewController(t)
+
+		m1 := &checksummedMigration{newMockMigration(ctrl, "1")}
+
+		source := NewMockSource(ctrl)
+		source.EXPECT().
+			Load(ctx).
+			Return(RepositoryBuilder().WithMigration(m1).Build(), nil)
+
+		target := newTarget(ctrl)
+
+		_, err := RepairChecksums(ctx, source, target, "4")
+		require.ErrorIs(t, err, ErrMigrationNotFound)
+		assert.Empty(t, target.updated)
+	})
+}
@@ -3,7 +3,6 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"path"
 	"strconv"
 	"strings"
 	"time"
@@ -41,9 +40,29 @@ $ migrations create --destination=./migrations Create customers table
 If the description is not provided, the this command will ask for it.
 
 $ migrations create --destination=./migrations
+
+To fill the migration files from a template, built-in or a directory with the do.sql and undo.sql templates, rendered
+with the {{.ID}}, {{.Description}} and {{.Timestamp}} of the migration:
+
+$ migrations create --destination=./migrations --template=create-table --undo Create customers table
+
+Existing files are never overwritten.
+
+To create a Go migration, using the fnc package, in the package of the destination folder:
+
+$ migrations create --destination=./migrations --extension=go --undo Backfill customer names
+
+The migration is added to the source variable of the package (--source-variable), or to the default registry of fnc
+when the package does not declare it.
 `,
 	Example: `migrations create --destination=./migrations Create table transactions`,
 	Run: func(cmd *cobra.Command, args []string) {
+		// Flags are validated before asking for the description, or creating any file.
+		if extension == "go" && migrationTemplate != "" {
+			_, _ = fmt.Fprintf(os.Stderr, "failed creating the migration: %s\n", colorError("--template is not supported by Go migrations"))
+			os.Exit(1)
+		}
+
 		var description string
 		if len(args) == 0 {
 			err := survey.AskOne(&survey.Input{
@@ -57,51 +76,59 @@ $ migrations create --destination=./migrations
 			description = strings.Join(args, " ")
 		}
 
+		now := time.Now().UTC()
 		var id string
 		switch format {
 		case "unix":
-			id = strconv.FormatInt(time.Now().UTC().Unix(), 10)
+			id = strconv.FormatInt(now.Unix(), 10)
 		default:
-			id = time.Now().UTC().Format(format)
-		}
-		var suffix string
-		if withDown {
-			suffix = "up"
-		} else if withUndo {
-			suffix = "do"
-		}
-		migrationFilePath := []string{path.Join(destination, migrationFileName(id, description, suffix, extension))}
-		if withDown {
-			suffix = "down"
-		} else if withUndo {
-			suffix = "undo"
+			id = now.Format(format)
 		}
-		if suffix != "" {
-			migrationFilePath = append(migrationFilePath, path.Join(destination, migrationFileName(id, description, suffix, extension)))
-		}
-
-		for _, m := range migrationFilePath {
-			err := createMigrationFile(m)
+		if extension == "go" {
+			m, err := createGoMigration(id, description, withUndo || withDown)
 			if err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "failed creating the migration: %s\n", colorError(err.Error()))
 				os.Exit(1)
 			}
 			fmt.Printf("migration %s was created", colorHighlight(m))
 			fmt.Println()
+			return
+		}
+
+		created, err := createTemplateMigration(migrationTemplateData{
+			ID:          id,
+			Description: description,
+			Timestamp:   now,
+		})
+		for _, m := range created {
+			fmt.Printf("migration %s was created", colorHighlight(m))
+			fmt.Println()
+		}
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "failed creating the migration: %s\n", colorError(err.Error()))
+			os.Exit(1)
 		}
 	},
 }
 
-func createMigrationFile(migrationFilePath string) error {
-	f, err := os.Create(migrationFilePath)
+// migrationFile is a file of the migration, with the part (do or undo) of the template that renders its content.
+type migrationFile struct {
+	path string
+	part string
+}
+
+// createMigrationFile creates the migration file with the given content. Existing files are never overwritten.
+func createMigrationFile(migrationFilePath string, content []byte) error {
+	f, err := os.OpenFile(migrationFilePath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
 	if err != nil {
-		_, _ = fmt.Fprintf(os.Stderr, "failed creating the migration file (%s): %s", migrationFilePath, colorError(err.Error()))
 		return err
 	}
 	defer func() {
 		_ = f.Close()
 	}()
 
-	return nil
+	_, err = f.Write(content)
+	return err
 }
 
 func migrationFileName(id string, description string, suffix string, e string) string {
@@ -130,7 +157,9 @@ func init() {
 	createCmd.Flags().StringVarP(&destination, "destination", "d", destination, "Folder where the migrations file will be created")
 	createCmd.Flags().StringVarP(&format, "format", "f", format, "Format of the migration ID (default, unix, any time.Time.Format supported)")
 	createCmd.Flags().StringVarP(&extension, "extension", "e", extension, "Extension of the migration that will be created")
+	createCmd.Flags().StringVarP(&migrationTemplate, "template", "t", migrationTemplate, "Template of the migration content: a built-in one (create-table, add-column, create-index) or a directory with the do.sql and undo.sql templates")
 	createCmd.Flags().BoolVar(&withUndo, "undo", withUndo, "Enable undo file")
 	createCmd.Flags().BoolVar(&withDown, "down", withDown, "Enable down file")
+	createCmd.Flags().StringVar(&goSourceVariable, "source-variable", goSourceVariable, "Variable of the package, with the migrations.Source, that Go migrations are added to")
 	createCmd.Flags().BoolVarP(&withUndo, "interactive", "i", withUndo, "Enable interactive mode")
 }
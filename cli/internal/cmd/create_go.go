@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	goformat "go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+var goSourceVariable = "Source"
+
+var goMigrationTemplate = template.Must(template.New("migration").Parse(`package {{.Package}}
+
+import (
+	"context"
+
+	"github.com/jamillosantos/migrations/v2/fnc"
+)
+
+{{if .Undo -}}
+var _ = fnc.Migration2(func(ctx context.Context) error {
+	// TODO: Implement the migration.
+	return nil
+}, func(ctx context.Context) error {
+	// TODO: Implement the undo of the migration.
+	return nil
+}, {{.Option}})
+{{- else -}}
+var _ = fnc.Migration(func(ctx context.Context) error {
+	// TODO: Implement the migration.
+	return nil
+}, {{.Option}})
+{{- end}}
+`))
+
+// createGoMigration creates a fnc migration skeleton, that compiles as is, in the destination folder. The migration is
+// added to the source variable of the package with fnc.WithSource or, when the package does not declare it, to the
+// default registry with fnc.Register.
+func createGoMigration(id, description string, undo bool) (string, error) {
+	packageName, err := goPackageName(destination)
+	if err != nil {
+		return "", err
+	}
+
+	// The source variable is only used when the package declares it, so the skeleton compiles.
+	option := "fnc.Register()"
+	if goSourceVariable != "" {
+		declared, err := goDeclares(destination, goSourceVariable)
+		if err != nil {
+			return "", err
+		}
+		if declared {
+			option = fmt.Sprintf("fnc.WithSource(%s)", goSourceVariable)
+		}
+	}
+
+	migrationFilePath := path.Join(destination, goMigrationFileName(id, description))
+	content, err := renderGoFile(migrationFilePath, goMigrationTemplate, map[string]any{
+		"Package": packageName,
+		"Undo":    undo,
+		"Option":  option,
+	})
+	if err != nil {
+		return "", err
+	}
+	err = createMigrationFile(migrationFilePath, content)
+	if err != nil {
+		return "", err
+	}
+	return migrationFilePath, nil
+}
+
+// goFileNameSuffixes are the last words of a Go file name that leave it out of the package build: `_test` and the
+// GOOS and GOARCH values known by go/build.
+var goFileNameSuffixes = map[string]struct{}{
+	"test": {},
+
+	"aix": {}, "android": {}, "darwin": {}, "dragonfly": {}, "freebsd": {}, "hurd": {}, "illumos": {}, "ios": {},
+	"js": {}, "linux": {}, "nacl": {}, "netbsd": {}, "openbsd": {}, "plan9": {}, "solaris": {}, "wasip1": {},
+	"windows": {}, "zos": {},
+
+	"386": {}, "amd64": {}, "amd64p32": {}, "arm": {}, "armbe": {}, "arm64": {}, "arm64be": {}, "loong64": {},
+	"mips": {}, "mipsle": {}, "mips64": {}, "mips64le": {}, "mips64p32": {}, "mips64p32le": {}, "ppc": {}, "ppc64": {},
+	"ppc64le": {}, "riscv": {}, "riscv64": {}, "s390": {}, "s390x": {}, "sparc": {}, "sparc64": {}, "wasm": {},
+}
+
+// goMigrationFileName returns the name of the Go migration file. A description ending in a word that would make it a
+// test file, or constrain it to a GOOS or GOARCH, is suffixed by "migration", so the file is always built.
+func goMigrationFileName(id, description string) string {
+	name := strings.TrimSuffix(migrationFileName(id, description, "", "go"), ".go")
+	if _, ok := goFileNameSuffixes[name[strings.LastIndex(name, "_")+1:]]; ok {
+		name += "_migration"
+	}
+	return name + ".go"
+}
+
+// goPackageName returns the name of the package declared by the Go files of the folder. When there are none, the name
+// is derived from the name of the folder.
+func goPackageName(folder string) (string, error) {
+	entries, err := os.ReadDir(folder)
+	if err != nil {
+		return "", fmt.Errorf("failed listing the destination folder: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") || strings.HasSuffix(entry.Name(), "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(token.NewFileSet(), filepath.Join(folder, entry.Name()), nil, parser.PackageClauseOnly)
+		if err != nil {
+			return "", fmt.Errorf("failed detecting the package name: %w", err)
+		}
+		return f.Name.Name, nil
+	}
+
+	abs, err := filepath.Abs(folder)
+	if err != nil {
+		return "", err
+	}
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, filepath.Base(abs))
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = "migrations" + name
+	}
+	return name, nil
+}
+
+// goDeclares reports whether the Go files of the folder declare a package level variable with the given name.
+func goDeclares(folder, name string) (bool, error) {
+	entries, err := os.ReadDir(folder)
+	if err != nil {
+		return false, fmt.Errorf("failed listing the destination folder: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") {
+			continue
+		}
+		f, err := parser.ParseFile(token.NewFileSet(), filepath.Join(folder, entry.Name()), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return false, fmt.Errorf("failed parsing %s: %w", entry.Name(), err)
+		}
+		for _, decl := range f.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.VAR {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				for _, ident := range spec.(*ast.ValueSpec).Names {
+					if ident.Name == name {
+						return true, nil
+					}
+				}
+			}
+		}
+	}
+	return false, nil
+}
+
+// renderGoFile renders the template of the file, formatted by gofmt.
+func renderGoFile(file string, tmpl *template.Template, data map[string]any) ([]byte, error) {
+	var buf bytes.Buffer
+	err := tmpl.Execute(&buf, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed rendering %s: %w", file, err)
+	}
+	content, err := goformat.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed formatting %s: %w", file, err)
+	}
+	return content, nil
+}
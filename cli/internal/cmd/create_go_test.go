@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newGoModule creates a module, in a temporary folder, that uses this repository as the migrations module.
+func newGoModule(t *testing.T) string {
+	t.Helper()
+	root, err := filepath.Abs("../../..")
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	goMod := "module example.com/app\n\ngo 1.23\n\nrequire github.com/jamillosantos/migrations/v2 v2.0.0\n\nreplace github.com/jamillosantos/migrations/v2 => " + root + "\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0o644))
+	goSum, err := os.ReadFile(filepath.Join(root, "go.sum"))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.sum"), goSum, 0o644))
+	return dir
+}
+
+// runGo runs the go command in the folder, failing the test when it fails.
+func runGo(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("go", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod")
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(output))
+}
+
+func TestCreateGoMigration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the created migrations with the go command")
+	}
+
+	t.Run("should create migrations that compile in the default registry", func(t *testing.T) {
+		dir := newGoModule(t)
+		folder := filepath.Join(dir, "migrations")
+		require.NoError(t, os.Mkdir(folder, 0o755))
+		setFlag(t, &destination, folder)
+		setFlag(t, &goSourceVariable, "Source")
+
+		m1, err := createGoMigration("1", "Create customers", false)
+		require.NoError(t, err)
+		m2, err := createGoMigration("2", "Backfill names", true)
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(folder, "1_create_customers.go"), m1)
+		assert.Equal(t, filepath.Join(folder, "2_backfill_names.go"), m2)
+
+		content, err := os.ReadFile(m1)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "package migrations")
+		assert.Contains(t, string(content), "fnc.Migration(")
+		assert.Contains(t, string(content), "fnc.Register()")
+		content, err = os.ReadFile(m2)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "fnc.Migration2(")
+
+		runGo(t, dir, "build", "./...")
+		runGo(t, dir, "vet", "./...")
+	})
+
+	t.Run("should create migrations that compile in the source variable of the package", func(t *testing.T) {
+		dir := newGoModule(t)
+		folder := filepath.Join(dir, "migrations")
+		require.NoError(t, os.Mkdir(folder, 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(folder, "source.go"), []byte("package billing\n\nimport \"github.com/jamillosantos/migrations/v2\"\n\nvar Migrations = migrations.NewMemorySource()\n"), 0o644))
+		setFlag(t, &destination, folder)
+		setFlag(t, &goSourceVariable, "Migrations")
+
+		m1, err := createGoMigration("1", "Create customers", false)
+		require.NoError(t, err)
+		m2, err := createGoMigration("2", "Backfill names", true)
+		require.NoError(t, err)
+
+		for _, m := range []string{m1, m2} {
+			content, err := os.ReadFile(m)
+			require.NoError(t, err)
+			assert.Contains(t, string(content), "package billing")
+			assert.Contains(t, string(content), "fnc.WithSource(Migrations)")
+		}
+
+		runGo(t, dir, "build", "./...")
+		runGo(t, dir, "vet", "./...")
+	})
+
+	t.Run("should create migrations that compile when the description ends in a build constraint", func(t *testing.T) {
+		dir := newGoModule(t)
+		folder := filepath.Join(dir, "migrations")
+		require.NoError(t, os.Mkdir(folder, 0o755))
+		setFlag(t, &destination, folder)
+		setFlag(t, &goSourceVariable, "Source")
+
+		m1, err := createGoMigration("1", "Seed test", false)
+		require.NoError(t, err)
+		m2, err := createGoMigration("2", "Fix windows", true)
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(folder, "1_seed_test_migration.go"), m1)
+		assert.Equal(t, filepath.Join(folder, "2_fix_windows_migration.go"), m2)
+
+		// A file left out of the build does not fail it, so the files of the package are checked.
+		cmd := exec.Command("go", "list", "-f", "{{.GoFiles}}", "./migrations")
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod")
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+		assert.Equal(t, "[1_seed_test_migration.go 2_fix_windows_migration.go]\n", string(output))
+		runGo(t, dir, "vet", "./...")
+	})
+
+	t.Run("should not overwrite an existing migration", func(t *testing.T) {
+		setFlag(t, &destination, t.TempDir())
+		setFlag(t, &goSourceVariable, "Source")
+
+		_, err := createGoMigration("1", "Create customers", false)
+		require.NoError(t, err)
+		_, err = createGoMigration("1", "Create customers", true)
+		assert.ErrorIs(t, err, os.ErrExist)
+	})
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+)
+
+//go:embed templates
+var builtinTemplates embed.FS
+
+var migrationTemplate = ""
+
+// migrationTemplateData is the data available to the templates of the migration files.
+type migrationTemplateData struct {
+	ID          string
+	Description string
+	// Timestamp is the time, in UTC, the migration was created.
+	Timestamp time.Time
+}
+
+// loadMigrationTemplate loads the template with the given name. The name is either a directory, with the `do.sql` and
+// `undo.sql` files, or one of the built-in templates. An empty name is an empty template.
+func loadMigrationTemplate(name string) (fs.FS, error) {
+	if name == "" {
+		return nil, nil
+	}
+	if info, err := os.Stat(name); err == nil && info.IsDir() {
+		return os.DirFS(name), nil
+	}
+	if _, err := fs.Stat(builtinTemplates, "templates/"+name); err != nil {
+		return nil, fmt.Errorf("template %s is not a directory nor a built-in template (%s)", name, strings.Join(builtinTemplateNames(), ", "))
+	}
+	return fs.Sub(builtinTemplates, "templates/"+name)
+}
+
+func builtinTemplateNames() []string {
+	entries, _ := fs.ReadDir(builtinTemplates, "templates")
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+// createTemplateMigration creates the files of the migration, in the destination folder, rendered from the template.
+// It returns the files created, even when it fails. Existing files are never overwritten.
+func createTemplateMigration(data migrationTemplateData) ([]string, error) {
+	tmpl, err := loadMigrationTemplate(migrationTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("failed loading the template: %w", err)
+	}
+
+	var suffix string
+	if withDown {
+		suffix = "up"
+	} else if withUndo {
+		suffix = "do"
+	}
+	migrationFiles := []migrationFile{{path.Join(destination, migrationFileName(data.ID, data.Description, suffix, extension)), "do"}}
+	if withDown {
+		suffix = "down"
+	} else if withUndo {
+		suffix = "undo"
+	}
+	if suffix != "" {
+		migrationFiles = append(migrationFiles, migrationFile{path.Join(destination, migrationFileName(data.ID, data.Description, suffix, extension)), "undo"})
+	}
+
+	// All files are checked before creating any of them, so a migration is not left half created.
+	for _, m := range migrationFiles {
+		if _, err := os.Stat(m.path); err == nil {
+			return nil, fmt.Errorf("failed creating the migration file (%s): %w", m.path, fs.ErrExist)
+		}
+	}
+
+	created := make([]string, 0, len(migrationFiles))
+	for _, m := range migrationFiles {
+		content, err := renderMigrationTemplate(tmpl, m.part, data)
+		if err == nil {
+			err = createMigrationFile(m.path, content)
+		}
+		if err != nil {
+			return created, fmt.Errorf("failed creating the migration file (%s): %w", m.path, err)
+		}
+		created = append(created, m.path)
+	}
+	return created, nil
+}
+
+// renderMigrationTemplate renders the part (do or undo) of the template. Templates without the part render nothing.
+func renderMigrationTemplate(tmpl fs.FS, part string, data migrationTemplateData) ([]byte, error) {
+	if tmpl == nil {
+		return nil, nil
+	}
+	content, err := fs.ReadFile(tmpl, part+".sql")
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	t, err := template.New(part).Option("missingkey=error").Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing the %s template: %w", part, err)
+	}
+	var buf bytes.Buffer
+	err = t.Execute(&buf, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed rendering the %s template: %w", part, err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setFlag sets the flag variable for the test, restoring it afterwards.
+func setFlag[T any](t *testing.T, flag *T, value T) {
+	t.Helper()
+	old := *flag
+	t.Cleanup(func() {
+		*flag = old
+	})
+	*flag = value
+}
+
+func TestCreateTemplateMigration(t *testing.T) {
+	data := migrationTemplateData{
+		ID:          "20250109011242",
+		Description: "Create customers",
+		Timestamp:   time.Date(2025, 1, 9, 1, 12, 42, 0, time.UTC),
+	}
+
+	t.Run("should render the built-in template", func(t *testing.T) {
+		dir := t.TempDir()
+		setFlag(t, &destination, dir)
+		setFlag(t, &extension, "sql")
+		setFlag(t, &migrationTemplate, "create-table")
+		setFlag(t, &withUndo, true)
+
+		created, err := createTemplateMigration(data)
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			filepath.Join(dir, "20250109011242_create_customers.do.sql"),
+			filepath.Join(dir, "20250109011242_create_customers.undo.sql"),
+		}, created)
+
+		content, err := os.ReadFile(created[0])
+		require.NoError(t, err)
+		assert.Equal(t, `-- Create customers
+-- Created at 2025-01-09 01:12:42 UTC.
+
+CREATE TABLE table_name (
+  id BIGINT NOT NULL PRIMARY KEY,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`, string(content))
+		content, err = os.ReadFile(created[1])
+		require.NoError(t, err)
+		assert.Equal(t, "-- Create customers\n\nDROP TABLE table_name;\n", string(content))
+	})
+
+	t.Run("should not overwrite the files of a migration with the same ID", func(t *testing.T) {
+		dir := t.TempDir()
+		setFlag(t, &destination, dir)
+		setFlag(t, &extension, "sql")
+		setFlag(t, &migrationTemplate, "create-table")
+		setFlag(t, &withUndo, false)
+
+		created, err := createTemplateMigration(data)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(created[0], []byte("CREATE TABLE customers (id int);"), 0o644))
+
+		_, err = createTemplateMigration(data)
+		assert.ErrorIs(t, err, fs.ErrExist)
+		content, err := os.ReadFile(created[0])
+		require.NoError(t, err)
+		assert.Equal(t, "CREATE TABLE customers (id int);", string(content))
+
+		// The file is created exclusively, even if it shows up after the check.
+		assert.ErrorIs(t, createMigrationFile(created[0], nil), fs.ErrExist)
+	})
+
+	t.Run("should create empty files without a template", func(t *testing.T) {
+		dir := t.TempDir()
+		setFlag(t, &destination, dir)
+		setFlag(t, &extension, "sql")
+		setFlag(t, &migrationTemplate, "")
+		setFlag(t, &withUndo, false)
+
+		created, err := createTemplateMigration(data)
+		require.NoError(t, err)
+		require.Len(t, created, 1)
+		content, err := os.ReadFile(created[0])
+		require.NoError(t, err)
+		assert.Empty(t, content)
+	})
+
+	t.Run("should fail when the template does not exist", func(t *testing.T) {
+		setFlag(t, &destination, t.TempDir())
+		setFlag(t, &migrationTemplate, "unknown")
+
+		_, err := createTemplateMigration(data)
+		assert.ErrorContains(t, err, "add-column, create-index, create-table")
+	})
+}
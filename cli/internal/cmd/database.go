@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/spf13/cobra"
+
+	"github.com/jamillosantos/migrations/v2"
+	migrationsql "github.com/jamillosantos/migrations/v2/sql"
+	"github.com/jamillosantos/migrations/v2/sql/drivers"
+)
+
+var (
+	errMissingDSN = errors.New("missing --dsn")
+)
+
+var (
+	databaseDriver  = "postgres"
+	databaseDSN     = ""
+	databaseName    = ""
+	sourceFolders   = []string{"."}
+	sourceRecursive = false
+	tableName       = drivers.DefaultMigrationsTableName
+	createSchema    = false
+	envPlaceholders = false
+)
+
+// addDatabaseFlags adds the flags needed for connecting to the database and loading the migrations to the command.
+func addDatabaseFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&databaseDriver, "driver", databaseDriver, "Database driver (postgres, sqlite3)")
+	cmd.Flags().StringVar(&databaseDSN, "dsn", databaseDSN, "Data source name used for connecting to the database")
+	cmd.Flags().StringVar(&databaseName, "database-name", databaseName, "Name of the database, required by drivers that cannot detect it")
+	cmd.Flags().StringSliceVarP(&sourceFolders, "source", "s", sourceFolders, "Folders where the migration files are stored (repeatable)")
+	cmd.Flags().BoolVar(&sourceRecursive, "recursive", sourceRecursive, "Loads the migration files of the subdirectories of the source folders as well")
+	cmd.Flags().StringVar(&tableName, "table", tableName, "Name of the table that stores the applied migrations, optionally qualified by its schema (eg. ops._migrations)")
+	cmd.Flags().BoolVar(&envPlaceholders, "env-placeholders", envPlaceholders, "Replaces the ${NAME} placeholders of the migration files by the environment variables")
+	cmd.Flags().BoolVar(&createSchema, "create-schema", createSchema, "Creates the schema of the migrations table when it does not exist (postgres)")
+}
+
+// environment is the database connection, with the source and target, used by the commands that inspect or change the
+// state of the migrations.
+type environment struct {
+	db     *sql.DB
+	source migrations.Source
+	target *migrationsql.Target
+}
+
+func openEnvironment(ctx context.Context) (*environment, error) {
+	if databaseDSN == "" {
+		return nil, errMissingDSN
+	}
+
+	db, err := sql.Open(databaseDriver, databaseDSN)
+	if err != nil {
+		return nil, fmt.Errorf("failed opening the database: %w", err)
+	}
+
+	err = db.PingContext(ctx)
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed connecting to the database: %w", err)
+	}
+
+	if len(sourceFolders) == 0 {
+		sourceFolders = []string{"."}
+	}
+	sourceOptions := []migrationsql.SourceOption{migrationsql.WithDirectories(sourceFolders[1:]...)}
+	if sourceRecursive {
+		sourceOptions = append(sourceOptions, migrationsql.WithRecursive())
+	}
+	if envPlaceholders {
+		sourceOptions = append(sourceOptions, migrationsql.WithEnvPlaceholders())
+	}
+
+	source, err := migrationsql.SourceFromDirectory(func() migrationsql.DBExecer {
+		return db
+	}, sourceFolders[0], sourceOptions...)
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	driverOptions := []drivers.Option{drivers.WithContext(ctx)}
+	if databaseName != "" {
+		driverOptions = append(driverOptions, drivers.WithDatabaseName(databaseName))
+	}
+	if createSchema {
+		driverOptions = append(driverOptions, drivers.WithCreateSchema())
+	}
+
+	target, err := migrationsql.NewTarget(db, migrationsql.WithTableName(tableName), migrationsql.WithDriverOptions(driverOptions...))
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return &environment{
+		db:     db,
+		source: source,
+		target: target,
+	}, nil
+}
+
+// openMutableEnvironment opens the environment for the commands that change the migrations recorded. The migrations
+// table is created, or upgraded, first. Existing data is not changed.
+func openMutableEnvironment(ctx context.Context) (*environment, error) {
+	env, err := openEnvironment(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = env.target.Create(ctx)
+	if err != nil {
+		env.Close()
+		return nil, err
+	}
+	return env, nil
+}
+
+func (env *environment) Close() {
+	_ = env.db.Close()
+}
@@ -0,0 +1,4 @@
+-- {{.Description}}
+-- Created at {{.Timestamp.Format "2006-01-02 15:04:05"}} UTC.
+
+ALTER TABLE table_name ADD column_name TEXT;
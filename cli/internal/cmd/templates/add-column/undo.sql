@@ -0,0 +1,3 @@
+-- {{.Description}}
+
+ALTER TABLE table_name DROP COLUMN column_name;
@@ -0,0 +1,3 @@
+-- {{.Description}}
+
+DROP INDEX index_name;
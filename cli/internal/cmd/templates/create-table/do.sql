@@ -0,0 +1,7 @@
+-- {{.Description}}
+-- Created at {{.Timestamp.Format "2006-01-02 15:04:05"}} UTC.
+
+CREATE TABLE table_name (
+  id BIGINT NOT NULL PRIMARY KEY,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,3 @@
+-- {{.Description}}
+
+DROP TABLE table_name;
@@ -0,0 +1,43 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// dependencyKey is the context key of the dependency of type T.
+type dependencyKey[T any] struct{}
+
+// ContextWithDependency returns a copy of ctx that carries the dependency, so code migrations can obtain it with
+// Dependency. Dependencies are identified by their type, a dependency of the same type replaces the previous one.
+func ContextWithDependency[T any](ctx context.Context, dependency T) context.Context {
+	return context.WithValue(ctx, dependencyKey[T]{}, dependency)
+}
+
+// Dependency returns the dependency of type T carried by the context, or an ErrMissingDependency naming the type if
+// there is none.
+func Dependency[T any](ctx context.Context) (T, error) {
+	dependency, ok := ctx.Value(dependencyKey[T]{}).(T)
+	if !ok {
+		return dependency, fmt.Errorf("%w: %s", ErrMissingDependency, reflect.TypeOf((*T)(nil)).Elem())
+	}
+	return dependency, nil
+}
+
+// WithDependency makes the runner provide the dependency, of type T, to the migrations it executes. Code migrations
+// obtain it with Dependency (see `fnc.MigrationWith`), so they do not rely on package globals and tests can provide
+// fakes.
+//
+// Example:
+//
+//	migrations.Migrate(ctx, source, target, migrations.WithRunnerOptions(
+//		migrations.WithDependency[CustomerRepository](customerRepository),
+//	))
+func WithDependency[T any](dependency T) RunnerOption {
+	return func(options *runnerOptions) {
+		options.Dependencies = append(options.Dependencies, func(ctx context.Context) context.Context {
+			return ContextWithDependency(ctx, dependency)
+		})
+	}
+}
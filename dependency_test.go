@@ -0,0 +1,68 @@
+package migrations
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type customerRepository interface {
+	Name() string
+}
+
+type fakeCustomerRepository struct{}
+
+func (fakeCustomerRepository) Name() string {
+	return "fake"
+}
+
+func TestDependency(t *testing.T) {
+	t.Run("should return the dependency carried by the context", func(t *testing.T) {
+		ctx := ContextWithDependency[customerRepository](context.Background(), fakeCustomerRepository{})
+		ctx = ContextWithDependency(ctx, 42)
+
+		got, err := Dependency[customerRepository](ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "fake", got.Name())
+
+		number, err := Dependency[int](ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 42, number)
+	})
+
+	t.Run("should fail when the context does not carry the dependency", func(t *testing.T) {
+		_, err := Dependency[customerRepository](context.Background())
+		assert.ErrorIs(t, err, ErrMissingDependency)
+		assert.ErrorContains(t, err, "migrations.customerRepository")
+	})
+}
+
+func TestRunner_Execute_Dependencies(t *testing.T) {
+	t.Run("should provide the dependencies to the migrations", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ctx := context.Background()
+
+		target := NewMockTarget(ctrl)
+		runner := NewRunner(NewMockSource(ctrl), target, WithDependency[customerRepository](fakeCustomerRepository{}))
+
+		m1 := newMockMigration(ctrl, "1")
+		target.EXPECT().Add(gomock.Any(), m1.ID()).Return(nil)
+		target.EXPECT().FinishMigration(gomock.Any(), m1.ID()).Return(nil)
+		m1.EXPECT().Do(gomock.Any()).DoAndReturn(func(ctx context.Context) error {
+			repository, err := Dependency[customerRepository](ctx)
+			require.NoError(t, err)
+			assert.Equal(t, "fake", repository.Name())
+			return nil
+		})
+
+		_, err := runner.Execute(ctx, &ExecuteRequest{
+			Plan: Plan{
+				{Action: ActionTypeDo, Migration: m1},
+			},
+		})
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,107 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ExistenceChecker is an optional interface for Targets that can tell whether they were created. The dry-run mode, and
+// the status report, treat a Target that was not created yet as having no migrations applied, instead of failing.
+type ExistenceChecker interface {
+	// Exists reports whether the Target was created by Target.Create.
+	Exists(ctx context.Context) (bool, error)
+}
+
+// ScriptedMigration is an optional interface for migrations that can show what they execute. It is used by the dry-run
+// mode to display the content of the migrations without running them.
+type ScriptedMigration interface {
+	// Script returns the content that is executed by the given action.
+	Script(action ActionType) string
+}
+
+// writeDryRun writes a description of every action of the plan into w. The content of migrations implementing the
+// ScriptedMigration is written as is, while other migrations (eg. code migrations) are written as a placeholder.
+func writeDryRun(w io.Writer, plan Plan) error {
+	_, err := fmt.Fprintf(w, "-- dry run: %d actions planned\n", len(plan))
+	if err != nil {
+		return err
+	}
+	for i, action := range plan {
+		var outOfOrder string
+		if action.OutOfOrder {
+			outOfOrder = ", out of order"
+		}
+		_, err = fmt.Fprintf(w, "\n-- [%d/%d] %s %s (%s%s)\n", i+1, len(plan), action.Action, action.Migration.ID(), action.Migration.String(), outOfOrder)
+		if err != nil {
+			return err
+		}
+
+		script := "-- code migration, its content cannot be displayed"
+		if m, ok := action.Migration.(ScriptedMigration); ok {
+			script = strings.TrimRight(m.Script(action.Action), "\n")
+		}
+		_, err = fmt.Fprintln(w, script)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dryRun resolves the plan and writes it into w. The Target is neither locked nor created, so a dry run does not change
+// its state. If the Target reports, through ExistenceChecker, that it was not created yet, the plan is resolved as if no
+// migration was applied.
+func dryRun(ctx context.Context, runner *Runner, planner ActionPLanner, w io.Writer) (ExecutionResponse, error) {
+	target := runner.target
+	if checker, ok := target.(ExistenceChecker); ok {
+		exists, err := checker.Exists(ctx)
+		if err != nil {
+			return ExecutionResponse{}, err
+		}
+		if !exists {
+			target = newUncreatedTarget(target)
+		}
+	}
+
+	dryRunner := *runner
+	dryRunner.target = target
+	plan, err := planner(dryRunner.source, target).Plan(ctx)
+	if err != nil {
+		return ExecutionResponse{}, err
+	}
+	return dryRunner.Execute(ctx, &ExecuteRequest{
+		Plan:   plan,
+		DryRun: w,
+	})
+}
+
+// uncreatedTarget is a Target that was not created yet, so it has no migrations applied. It is only read by the
+// dry-run mode.
+type uncreatedTarget struct {
+	Target
+}
+
+// uncreatedTransactionalTarget keeps the TransactionalTarget of the Target, so the transaction mode of the runner is
+// still checked by the dry run.
+type uncreatedTransactionalTarget struct {
+	uncreatedTarget
+	TransactionalTarget
+}
+
+func newUncreatedTarget(target Target) Target {
+	uncreated := uncreatedTarget{target}
+	if txTarget, ok := target.(TransactionalTarget); ok {
+		return uncreatedTransactionalTarget{uncreated, txTarget}
+	}
+	return uncreated
+}
+
+func (uncreatedTarget) Current(_ context.Context) (string, error) {
+	return "", ErrNoCurrentMigration
+}
+
+func (uncreatedTarget) Done(_ context.Context) ([]string, error) {
+	return []string{}, nil
+}
@@ -2,6 +2,7 @@ package migrations
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 )
 
@@ -30,6 +31,36 @@ var (
 	ErrInvalidAction = errors.New("undefined action")
 
 	ErrDirtyMigration = errors.New("migration was started but not completed and now it is in a dirty state")
+
+	// ErrMigrationNotDirty is returned when recovering a migration from the dirty state, but it is not dirty.
+	ErrMigrationNotDirty = errors.New("migration is not dirty")
+
+	// ErrDirtyNotSupported is returned when listing the dirty migrations of a Target that does not implement
+	// HistoryTarget.
+	ErrDirtyNotSupported = errors.New("target does not support listing dirty migrations")
+
+	// ErrTransactionNotSupported is returned when the Runner is set to use transactions but the Target does not
+	// implement TransactionalTarget.
+	ErrTransactionNotSupported = errors.New("target does not support transactions")
+
+	// ErrNonTransactionalMigration is returned when a migration that cannot run inside of a transaction is part of a plan
+	// that should be executed in a single transaction.
+	ErrNonTransactionalMigration = errors.New("migration cannot run inside of a transaction")
+
+	// ErrChecksumMismatch is returned when the content of an applied migration does not match the checksum recorded
+	// when it was applied.
+	ErrChecksumMismatch = errors.New("migration content changed after being applied")
+
+	// ErrChecksumNotSupported is returned when checksums are verified, or repaired, but the Target does not record
+	// them.
+	ErrChecksumNotSupported = errors.New("target does not support checksums")
+
+	// ErrRequiredMigrationNotApplied is returned when a migration is planned before the migrations it requires are
+	// applied.
+	ErrRequiredMigrationNotApplied = errors.New("required migration is not applied")
+
+	// ErrMissingDependency is returned when a migration needs a dependency that was not provided (see WithDependency).
+	ErrMissingDependency = errors.New("missing dependency")
 )
 
 // ---------------------------------------------------------------------------------------------------------------------
@@ -156,17 +187,83 @@ func (err *migrationIDError) Error() string {
 
 // ---------------------------------------------------------------------------------------------------------------------
 
+// ChecksumMismatchError is returned when applied migrations had their content changed. Each migration is wrapped, using
+// WrapMigration, with ErrChecksumMismatch.
+type ChecksumMismatchError struct {
+	errs []error
+}
+
+func newChecksumMismatchError(migrations ...Migration) *ChecksumMismatchError {
+	errs := make([]error, len(migrations))
+	for i, migration := range migrations {
+		errs[i] = WrapMigration(ErrChecksumMismatch, migration)
+	}
+	return &ChecksumMismatchError{errs}
+}
+
+func (err *ChecksumMismatchError) Migrations() []Migration {
+	result := make([]Migration, len(err.errs))
+	for i, mErr := range err.errs {
+		result[i] = mErr.(MigrationError).Migration()
+	}
+	return result
+}
+
+func (err *ChecksumMismatchError) Unwrap() []error {
+	return err.errs
+}
+
+func (err *ChecksumMismatchError) Error() string {
+	var r strings.Builder
+	r.WriteString(ErrChecksumMismatch.Error())
+	r.WriteString(": ")
+	for i, migration := range err.Migrations() {
+		if i > 0 {
+			r.WriteString(", ")
+		}
+		r.WriteString(migration.String())
+	}
+	return r.String()
+}
+
+// ---------------------------------------------------------------------------------------------------------------------
+
 type QueryError interface {
 	Query() string
 }
 
+// StatementQueryError is a QueryError of a migration whose content is executed statement by statement.
+type StatementQueryError interface {
+	QueryError
+	// Statement is the position, starting from 1, of the failing statement in the migration.
+	Statement() int
+}
+
 type queryError struct {
 	error
-	query string
+	query     string
+	statement int
 }
 
 func NewQueryError(err error, query string) error {
-	return &queryError{err, query}
+	return &queryError{error: err, query: query}
+}
+
+// NewStatementQueryError creates a StatementQueryError for the statement, at the given position (starting from 1), of
+// a migration.
+func NewStatementQueryError(err error, query string, statement int) error {
+	return &queryError{error: err, query: query, statement: statement}
+}
+
+func (err *queryError) Error() string {
+	if err.statement == 0 {
+		return err.error.Error()
+	}
+	return fmt.Sprintf("statement %d: %s", err.statement, err.error.Error())
+}
+
+func (err *queryError) Statement() int {
+	return err.statement
 }
 
 func (err *queryError) Unwrap() error {
@@ -9,7 +9,8 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/jamillosantos/migrations/v2"
-	fcnmigrations "github.com/jamillosantos/migrations/v2/examples/fncmigrations/migrations"
+	_ "github.com/jamillosantos/migrations/v2/examples/fncmigrations/migrations"
+	"github.com/jamillosantos/migrations/v2/fnc"
 	"github.com/jamillosantos/migrations/v2/reporters"
 	migrationsql "github.com/jamillosantos/migrations/v2/sql"
 )
@@ -31,19 +32,14 @@ func main() {
 
 	ctx := context.Background()
 
-	s, err := migrationsql.SourceFromFS(func() migrationsql.DBExecer {
+	sqlSource, err := migrationsql.SourceFromFS(func() migrationsql.DBExecer {
 		return db
 	}, migrationsFolder, "migrations")
 	if err != nil {
 		panic(err)
 	}
 
-	for _, m := range fcnmigrations.CodeMigrations {
-		err := s.Add(ctx, m)
-		if err != nil {
-			panic(err)
-		}
-	}
+	s := migrations.MultiSource(sqlSource, fnc.Source())
 
 	t, err := migrationsql.NewTarget(db)
 	if err != nil {
@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"os"
+
+	"github.com/jamillosantos/migrations/v2/fnc"
 )
 
-var _ = Migration(func(ctx context.Context) error {
+var _ = fnc.Migration(func(ctx context.Context) error {
 	fmt.Fprintln(os.Stderr, "This will do some migration stuff.")
 
 	return nil
-})
+}, fnc.Register())
@@ -0,0 +1,50 @@
+package fnc
+
+import (
+	"context"
+
+	"github.com/jamillosantos/migrations/v2"
+)
+
+// MigrationWith works as the Migration, but the function receives the dependency of type T provided to the Runner by
+// migrations.WithDependency. When it is not provided, the migration fails with migrations.ErrMissingDependency.
+//
+// Dependencies are looked up by the exact type they were provided with: a *Repo provided by
+// migrations.WithDependency(repo) does not satisfy a MigrationWith[RepoInterface]. Provide it as
+// migrations.WithDependency[RepoInterface](repo) instead.
+//
+// Example:
+//
+//	var _ = fnc.MigrationWith(func(ctx context.Context, customers CustomerRepository) error {
+//		return customers.Backfill(ctx)
+//	}, fnc.Register())
+//
+// MigrationWith can panic if the migration cannot be added to the source.
+func MigrationWith[T any](do func(ctx context.Context, dependency T) error, opts ...Option) migrations.Migration {
+	return newMigration(opts, func(file string) migrations.Migration {
+		return createMigration(file, withDependency(do), nil)
+	})
+}
+
+// Migration2With works as the Migration2, but the functions receive the dependency of type T. See MigrationWith.
+//
+// Migration2With can panic if the migration cannot be added to the source.
+func Migration2With[T any](do, undo func(ctx context.Context, dependency T) error, opts ...Option) migrations.Migration {
+	return newMigration(opts, func(file string) migrations.Migration {
+		return createMigration(file, withDependency(do), withDependency(undo))
+	})
+}
+
+// withDependency adapts the fn to receive the dependency carried by the context.
+func withDependency[T any](fn func(ctx context.Context, dependency T) error) func(ctx context.Context) error {
+	if fn == nil {
+		return nil
+	}
+	return func(ctx context.Context) error {
+		dependency, err := migrations.Dependency[T](ctx)
+		if err != nil {
+			return err
+		}
+		return fn(ctx, dependency)
+	}
+}
@@ -0,0 +1,65 @@
+package fnc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jamillosantos/migrations/v2"
+)
+
+type logger struct {
+	lines []string
+}
+
+type lineWriter interface {
+	write(line string)
+}
+
+func (l *logger) write(line string) {
+	l.lines = append(l.lines, line)
+}
+
+func TestMigrationWith(t *testing.T) {
+	t.Run("should receive the dependency carried by the context", func(t *testing.T) {
+		m := Migration2With(func(ctx context.Context, l *logger) error {
+			l.lines = append(l.lines, "do")
+			return nil
+		}, func(ctx context.Context, l *logger) error {
+			l.lines = append(l.lines, "undo")
+			return nil
+		})
+
+		l := &logger{}
+		ctx := migrations.ContextWithDependency(context.Background(), l)
+		require.NoError(t, m.Do(ctx))
+		require.True(t, m.CanUndo())
+		require.NoError(t, m.Undo(ctx))
+		assert.Equal(t, []string{"do", "undo"}, l.lines)
+		assert.Equal(t, "dependency", m.ID())
+	})
+
+	t.Run("should fail when the dependency is not provided", func(t *testing.T) {
+		m := MigrationWith(func(ctx context.Context, l *logger) error {
+			return nil
+		})
+
+		assert.ErrorIs(t, m.Do(context.Background()), migrations.ErrMissingDependency)
+		assert.False(t, m.CanUndo())
+	})
+	t.Run("should look up the dependency by the exact type it was provided with", func(t *testing.T) {
+		m := MigrationWith(func(ctx context.Context, w lineWriter) error {
+			w.write("do")
+			return nil
+		})
+
+		l := &logger{}
+		err := m.Do(migrations.ContextWithDependency(context.Background(), l))
+		assert.ErrorIs(t, err, migrations.ErrMissingDependency)
+
+		require.NoError(t, m.Do(migrations.ContextWithDependency[lineWriter](context.Background(), l)))
+		assert.Equal(t, []string{"do"}, l.lines)
+	})
+}
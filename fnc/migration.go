@@ -31,9 +31,10 @@ func getMigrationInfo(file string) (string, string, error) {
 }
 
 type migrationOpts struct {
-	skip    int
-	context context.Context
-	source  migrations.Source
+	skip     int
+	context  context.Context
+	source   migrations.Source
+	registry *Registry
 }
 
 // Option is a function that can be used to configure the Migration2 and Migration.
@@ -60,31 +61,34 @@ func WithSource(source migrations.Source) Option {
 	}
 }
 
-// Migration is a helper function to create a new forward migration based on the filename of the caller. The
-// difference between this and Migration2 is that this doesn't need the undo function.
-//
-// Migration can panic if the migration cannot be added to the source.
-func Migration(do func(ctx context.Context) error, opts ...Option) migrations.Migration {
-	o := defaultMigrationOpts()
-	for _, opt := range opts {
-		opt(&o)
-	}
-	if o.context == nil {
-		o.context = context.Background()
+// WithRegistry is an option to register the migration in the given Registry. See Register for using the default one.
+func WithRegistry(registry *Registry) Option {
+	return func(opts *migrationOpts) {
+		opts.registry = registry
 	}
+}
 
-	_, file, _, ok := runtime.Caller(o.skip)
-	if !ok {
-		panic(fmt.Errorf("%w: %s", ErrInvalidFilename, path.Base(file)))
-	}
-	m := createMigration(file, do, nil)
-	if o.source != nil {
-		err := o.source.Add(o.context, m)
+// register adds the migration, declared in the given file, to the source and registry of the options.
+func (opts migrationOpts) register(m migrations.Migration, file string) {
+	if opts.source != nil {
+		err := opts.source.Add(opts.context, m)
 		if err != nil {
 			panic(err)
 		}
 	}
-	return m
+	if opts.registry != nil {
+		opts.registry.add(m, file)
+	}
+}
+
+// Migration is a helper function to create a new forward migration based on the filename of the caller. The
+// difference between this and Migration2 is that this doesn't need the undo function.
+//
+// Migration can panic if the migration cannot be added to the source.
+func Migration(do func(ctx context.Context) error, opts ...Option) migrations.Migration {
+	return newMigration(opts, func(file string) migrations.Migration {
+		return createMigration(file, do, nil)
+	})
 }
 
 // Migration2 is a helper function to create a new migration based on the filename of the caller.
@@ -93,6 +97,14 @@ func Migration(do func(ctx context.Context) error, opts ...Option) migrations.Mi
 //
 // Migration2 can panic if the migration cannot be added to the source.
 func Migration2(do, undo func(ctx context.Context) error, opts ...Option) migrations.Migration {
+	return newMigration(opts, func(file string) migrations.Migration {
+		return createMigration(file, do, undo)
+	})
+}
+
+// newMigration applies the options and registers the migration built by create from the file of the caller of the
+// exported function.
+func newMigration(opts []Option, create func(file string) migrations.Migration) migrations.Migration {
 	o := defaultMigrationOpts()
 	for _, opt := range opts {
 		opt(&o)
@@ -101,17 +113,13 @@ func Migration2(do, undo func(ctx context.Context) error, opts ...Option) migrat
 		o.context = context.Background()
 	}
 
-	_, file, _, ok := runtime.Caller(o.skip)
+	// The skip counts from the exported function, that calls newMigration.
+	_, file, _, ok := runtime.Caller(o.skip + 1)
 	if !ok {
 		panic(fmt.Errorf("%w: %s", ErrInvalidFilename, path.Base(file)))
 	}
-	m := createMigration(file, do, undo)
-	if o.source != nil {
-		err := o.source.Add(o.context, m)
-		if err != nil {
-			panic(err)
-		}
-	}
+	m := create(file)
+	o.register(m, file)
 	return m
 }
 
@@ -0,0 +1,108 @@
+package fnc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/jamillosantos/migrations/v2"
+)
+
+var defaultRegistry = NewRegistry()
+
+// Registry collects the migrations declared, usually by package variables, with the WithRegistry option, so they can be
+// loaded by its Source without keeping a list of them by hand.
+type Registry struct {
+	mu      sync.Mutex
+	entries []registryEntry
+}
+
+type registryEntry struct {
+	migration migrations.Migration
+	// file is the Go file where the migration was declared.
+	file string
+}
+
+// NewRegistry creates an empty Registry. Use it for keeping the migrations of a package apart from the ones registered
+// by Register.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register is an option that registers the migration in the default registry, whose migrations are loaded by Source.
+//
+// Example:
+//
+//	var _ = fnc.Migration(func(ctx context.Context) error {
+//		return nil
+//	}, fnc.Register())
+func Register() Option {
+	return WithRegistry(defaultRegistry)
+}
+
+// Source returns a migrations.Source that loads the migrations of the default registry. See Register.
+func Source() migrations.Source {
+	return defaultRegistry.Source()
+}
+
+// Source returns a migrations.Source that loads the migrations of the registry.
+//
+// The same ID declared more than once fails the load with a migrations.ErrMigrationAlreadyExists naming both Go files.
+func (r *Registry) Source() migrations.Source {
+	return &registrySource{registry: r}
+}
+
+// add appends the migration to the registry. Duplicated IDs are kept, as package variables cannot fail, and reported
+// by Load.
+func (r *Registry) add(migration migrations.Migration, file string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, registryEntry{migration: migration, file: file})
+}
+
+// addUnique appends the migration to the registry, failing when its ID is already registered.
+func (r *Registry) addUnique(migration migrations.Migration, file string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	id := migration.ID()
+	for _, entry := range r.entries {
+		if entry.migration.ID() == id {
+			return duplicatedMigrationErr(id, entry.file, file)
+		}
+	}
+	r.entries = append(r.entries, registryEntry{migration: migration, file: file})
+	return nil
+}
+
+// duplicatedMigrationErr returns a migrations.ErrMigrationAlreadyExists naming the files declaring the same ID.
+func duplicatedMigrationErr(id, file, otherFile string) error {
+	return migrations.WrapMigrationID(fmt.Errorf("%w: %s and %s", migrations.ErrMigrationAlreadyExists, file, otherFile), id)
+}
+
+type registrySource struct {
+	registry *Registry
+}
+
+func (s *registrySource) Add(_ context.Context, migration migrations.Migration) error {
+	return s.registry.addUnique(migration, migration.String())
+}
+
+func (s *registrySource) Load(_ context.Context) (migrations.Repository, error) {
+	s.registry.mu.Lock()
+	defer s.registry.mu.Unlock()
+
+	var repo migrations.Repository
+	files := make(map[string]string, len(s.registry.entries))
+	for _, entry := range s.registry.entries {
+		id := entry.migration.ID()
+		if file, ok := files[id]; ok {
+			return migrations.Repository{}, duplicatedMigrationErr(id, file, entry.file)
+		}
+		err := repo.Add(entry.migration)
+		if err != nil {
+			return migrations.Repository{}, err
+		}
+		files[id] = entry.file
+	}
+	return repo, nil
+}
@@ -0,0 +1,65 @@
+package fnc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jamillosantos/migrations/v2"
+)
+
+func TestRegistry(t *testing.T) {
+	do := func(ctx context.Context) error {
+		return nil
+	}
+
+	t.Run("should load the registered migrations sorted by ID", func(t *testing.T) {
+		ctx := context.Background()
+		registry := NewRegistry()
+		m2 := migrations.NewMigration("2", "insert customers", do, nil)
+		registry.add(m2, "2_insert_customers.go")
+		m1 := Migration(do, WithRegistry(registry))
+
+		repo, err := registry.Source().Load(ctx)
+		require.NoError(t, err)
+		list, err := repo.List(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []migrations.Migration{m2, m1}, list)
+		assert.Equal(t, "registry", m1.ID())
+	})
+
+	t.Run("should fail when the same ID is registered twice", func(t *testing.T) {
+		registry := NewRegistry()
+		Migration(do, WithRegistry(registry))
+		Migration2(do, do, WithRegistry(registry))
+
+		_, err := registry.Source().Load(context.Background())
+		assert.ErrorIs(t, err, migrations.ErrMigrationAlreadyExists)
+		assert.Regexp(t, `registry_test\.go and .*registry_test\.go$`, err.Error())
+		var idErr migrations.MigrationIDError
+		require.ErrorAs(t, err, &idErr)
+		assert.Equal(t, "registry", idErr.MigrationID())
+	})
+	t.Run("should fail adding a migration whose ID is already registered", func(t *testing.T) {
+		ctx := context.Background()
+		registry := NewRegistry()
+		Migration(do, WithRegistry(registry))
+		source := registry.Source()
+
+		err := source.Add(ctx, migrations.NewMigration("registry", "duplicated", do, nil))
+		assert.ErrorIs(t, err, migrations.ErrMigrationAlreadyExists)
+		assert.Regexp(t, `registry_test\.go and registry_duplicated$`, err.Error())
+		var idErr migrations.MigrationIDError
+		require.ErrorAs(t, err, &idErr)
+		assert.Equal(t, "registry", idErr.MigrationID())
+
+		require.NoError(t, source.Add(ctx, migrations.NewMigration("2", "insert customers", do, nil)))
+		repo, err := source.Load(ctx)
+		require.NoError(t, err)
+		list, err := repo.List(ctx)
+		require.NoError(t, err)
+		assert.Len(t, list, 2)
+	})
+}
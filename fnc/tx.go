@@ -0,0 +1,71 @@
+package fnc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jamillosantos/migrations/v2"
+	"github.com/jamillosantos/migrations/v2/internal/sqltx"
+)
+
+// ErrNoTransaction is returned when a TxMigration runs with a context that does not carry a transaction, which happens
+// when the Target does not implement migrations.TransactionalTarget.
+var ErrNoTransaction = errors.New("migration requires a transaction")
+
+// TxFunc is the function of a TxMigration. It receives the transaction where the migration is recorded.
+type TxFunc func(ctx context.Context, tx *sql.Tx) error
+
+// txMigration is a migration that requires the transaction started by the sql.Target.
+type txMigration struct {
+	*migrations.BaseMigration
+}
+
+func (m *txMigration) RequiresTransaction() bool {
+	return true
+}
+
+// TxMigration works as the Migration, but the function receives the *sql.Tx started by the sql.Target. The migration
+// is recorded in the same transaction, so both are committed, or rolled back, as a unit, whatever the transaction mode
+// of the Runner.
+//
+// TxMigration can panic if the migration cannot be added to the source.
+func TxMigration(do TxFunc, opts ...Option) migrations.Migration {
+	return newMigration(opts, func(file string) migrations.Migration {
+		return createTxMigration(file, do, nil)
+	})
+}
+
+// TxMigration2 works as the Migration2, but the functions receive the *sql.Tx started by the sql.Target. See
+// TxMigration.
+//
+// TxMigration2 can panic if the migration cannot be added to the source.
+func TxMigration2(do, undo TxFunc, opts ...Option) migrations.Migration {
+	return newMigration(opts, func(file string) migrations.Migration {
+		return createTxMigration(file, do, undo)
+	})
+}
+
+func createTxMigration(file string, do, undo TxFunc) migrations.Migration {
+	id, description, err := getMigrationInfo(file)
+	if err != nil {
+		panic(fmt.Errorf("failed to get migration ID: %w", err))
+	}
+	var undoFn func(ctx context.Context) error
+	if undo != nil {
+		undoFn = withTx(undo)
+	}
+	return &txMigration{migrations.NewMigration(id, description, withTx(do), undoFn)}
+}
+
+// withTx adapts the fn to receive the transaction carried by the context.
+func withTx(fn TxFunc) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		tx := sqltx.FromContext(ctx)
+		if tx == nil {
+			return ErrNoTransaction
+		}
+		return fn(ctx, tx)
+	}
+}
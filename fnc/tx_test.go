@@ -0,0 +1,53 @@
+package fnc
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jamillosantos/migrations/v2"
+	"github.com/jamillosantos/migrations/v2/internal/sqltx"
+)
+
+func TestTxMigration(t *testing.T) {
+	t.Run("should receive the transaction carried by the context", func(t *testing.T) {
+		tx := &sql.Tx{}
+		var got, gotUndo *sql.Tx
+		m := TxMigration2(func(ctx context.Context, tx *sql.Tx) error {
+			got = tx
+			return nil
+		}, func(ctx context.Context, tx *sql.Tx) error {
+			gotUndo = tx
+			return nil
+		})
+
+		ctx := sqltx.WithTx(context.Background(), tx)
+		require.NoError(t, m.Do(ctx))
+		require.NoError(t, m.Undo(ctx))
+		assert.Same(t, tx, got)
+		assert.Same(t, tx, gotUndo)
+		assert.Equal(t, "tx", m.ID())
+	})
+
+	t.Run("should require a transaction", func(t *testing.T) {
+		m := TxMigration(func(ctx context.Context, tx *sql.Tx) error {
+			return nil
+		})
+
+		required, ok := m.(migrations.TransactionRequiredMigration)
+		require.True(t, ok)
+		assert.True(t, required.RequiresTransaction())
+	})
+
+	t.Run("should fail when the context does not carry a transaction", func(t *testing.T) {
+		m := TxMigration(func(ctx context.Context, tx *sql.Tx) error {
+			return nil
+		})
+
+		assert.ErrorIs(t, m.Do(context.Background()), ErrNoTransaction)
+		assert.False(t, m.CanUndo())
+	})
+}
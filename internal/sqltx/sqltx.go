@@ -0,0 +1,21 @@
+// Package sqltx carries the transaction started by the sql.Target in the context. It has no dependencies, so packages
+// like fnc can read the transaction without depending on the SQL drivers.
+package sqltx
+
+import (
+	"context"
+	"database/sql"
+)
+
+type contextKey struct{}
+
+// WithTx returns a copy of ctx that carries the given transaction.
+func WithTx(ctx context.Context, tx *sql.Tx) context.Context {
+	return context.WithValue(ctx, contextKey{}, tx)
+}
+
+// FromContext returns the transaction carried by the context, or nil if there is none.
+func FromContext(ctx context.Context) *sql.Tx {
+	tx, _ := ctx.Value(contextKey{}).(*sql.Tx)
+	return tx
+}
@@ -0,0 +1,93 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// MigrationMetadata are the details declared by a migration that change how it is planned and executed.
+type MigrationMetadata struct {
+	// Timeout limits the execution of the migration. Zero means no limit.
+	Timeout time.Duration
+	// Tags classify the migration (eg. "data", "slow"). They can be used by the planners to leave migrations out of a
+	// plan.
+	Tags []string
+	// Requires are the IDs of the migrations that must be applied before this one.
+	Requires []string
+}
+
+// MetadataMigration is an optional interface for migrations that declare MigrationMetadata. SQL migrations declare it
+// with directives in the header of their files.
+type MetadataMigration interface {
+	Metadata() MigrationMetadata
+}
+
+// migrationMetadata returns the metadata of the migration, or the zero value when it does not declare any.
+func migrationMetadata(migration Migration) MigrationMetadata {
+	m, ok := migration.(MetadataMigration)
+	if !ok {
+		return MigrationMetadata{}
+	}
+	return m.Metadata()
+}
+
+// HasTag reports whether the metadata has any of the given tags.
+func (metadata MigrationMetadata) HasTag(tags ...string) bool {
+	for _, tag := range metadata.Tags {
+		for _, t := range tags {
+			if tag == t {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// checkRequirements ensures the migrations required by each migration applied by the plan are applied, or applied
+// earlier by the plan itself. The applied migrations are only listed if any migration of the plan has requirements.
+func (runner *Runner) checkRequirements(ctx context.Context, plan Plan) error {
+	hasRequirements := false
+	for _, action := range plan {
+		if action.Action == ActionTypeDo && len(migrationMetadata(action.Migration).Requires) > 0 {
+			hasRequirements = true
+			break
+		}
+	}
+	if !hasRequirements {
+		return nil
+	}
+
+	done, err := runner.target.Done(ctx)
+	if err != nil {
+		return fmt.Errorf("failed listing migrations applied: %w", err)
+	}
+	applied := make(map[string]struct{}, len(done))
+	for _, migrationID := range done {
+		applied[migrationID] = struct{}{}
+	}
+
+	for _, action := range plan {
+		switch action.Action {
+		case ActionTypeDo:
+			for _, requiredID := range migrationMetadata(action.Migration).Requires {
+				if _, ok := applied[requiredID]; !ok {
+					return WrapMigration(fmt.Errorf("%w: %s", ErrRequiredMigrationNotApplied, requiredID), action.Migration)
+				}
+			}
+			applied[action.Migration.ID()] = struct{}{}
+		case ActionTypeUndo:
+			delete(applied, action.Migration.ID())
+		}
+	}
+	return nil
+}
+
+// withMigrationTimeout returns a context limited by the timeout of the migration, if it has one.
+func withMigrationTimeout(ctx context.Context, migration Migration) (context.Context, context.CancelFunc) {
+	timeout := migrationMetadata(migration).Timeout
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
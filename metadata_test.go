@@ -0,0 +1,167 @@
+package migrations
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type metadataMigration struct {
+	*MockMigration
+	metadata MigrationMetadata
+}
+
+func (m *metadataMigration) Metadata() MigrationMetadata {
+	return m.metadata
+}
+
+func TestRunner_Execute_Metadata(t *testing.T) {
+	t.Run("should execute a migration whose requirements are applied", func(t *testing.T) {
+		ctx := context.Background()
+		s := createRunner(t)
+
+		m2 := newMockMigration(s.ctrl, "2")
+		m3 := &metadataMigration{newMockMigration(s.ctrl, "3"), MigrationMetadata{Requires: []string{"1", "2"}}}
+
+		s.target.EXPECT().Done(ctx).Return([]string{"1"}, nil)
+		for _, m := range []Migration{m2, m3} {
+			s.target.EXPECT().Add(ctx, m.ID()).Return(nil)
+			s.target.EXPECT().FinishMigration(ctx, m.ID()).Return(nil)
+		}
+		m2.EXPECT().Do(ctx).Return(nil)
+		m3.EXPECT().Do(ctx).Return(nil)
+
+		stats, err := s.runner.Execute(ctx, &ExecuteRequest{
+			Plan: Plan{
+				{Action: ActionTypeDo, Migration: m2},
+				{Action: ActionTypeDo, Migration: m3},
+			},
+		})
+		require.NoError(t, err)
+		assert.Len(t, stats.Successful, 2)
+	})
+
+	t.Run("should fail when a requirement is not applied", func(t *testing.T) {
+		ctx := context.Background()
+		s := createRunner(t)
+
+		m2 := &metadataMigration{newMockMigration(s.ctrl, "2"), MigrationMetadata{Requires: []string{"1"}}}
+
+		s.target.EXPECT().Done(ctx).Return([]string{}, nil)
+
+		_, err := s.runner.Execute(ctx, &ExecuteRequest{
+			Plan: Plan{
+				{Action: ActionTypeDo, Migration: m2},
+			},
+		})
+		require.ErrorIs(t, err, ErrRequiredMigrationNotApplied)
+		var mErr MigrationError
+		require.ErrorAs(t, err, &mErr)
+		assert.Equal(t, m2, mErr.Migration())
+	})
+
+	t.Run("should fail when a requirement is undone by the plan", func(t *testing.T) {
+		ctx := context.Background()
+		s := createRunner(t)
+
+		m1 := newMockMigration(s.ctrl, "1")
+		m1.EXPECT().CanUndo().Return(true)
+		m2 := &metadataMigration{newMockMigration(s.ctrl, "2"), MigrationMetadata{Requires: []string{"1"}}}
+
+		s.target.EXPECT().Done(ctx).Return([]string{"1"}, nil)
+
+		_, err := s.runner.Execute(ctx, &ExecuteRequest{
+			Plan: Plan{
+				{Action: ActionTypeUndo, Migration: m1},
+				{Action: ActionTypeDo, Migration: m2},
+			},
+		})
+		require.ErrorIs(t, err, ErrRequiredMigrationNotApplied)
+	})
+
+	t.Run("should limit the execution by the timeout of the migration", func(t *testing.T) {
+		ctx := context.Background()
+		s := createRunner(t)
+
+		m1 := &metadataMigration{newMockMigration(s.ctrl, "1"), MigrationMetadata{Timeout: time.Minute}}
+
+		s.target.EXPECT().Add(ctx, m1.ID()).Return(nil)
+		s.target.EXPECT().FinishMigration(ctx, m1.ID()).Return(nil)
+		m1.EXPECT().Do(gomock.Any()).DoAndReturn(func(ctx context.Context) error {
+			deadline, ok := ctx.Deadline()
+			assert.True(t, ok)
+			assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, 5*time.Second)
+			return nil
+		})
+
+		_, err := s.runner.Execute(ctx, &ExecuteRequest{
+			Plan: Plan{
+				{Action: ActionTypeDo, Migration: m1},
+			},
+		})
+		require.NoError(t, err)
+	})
+}
+
+func Test_migratePlanner_Plan_ExcludeTags(t *testing.T) {
+	t.Run("should stop the plan before the first migration with an excluded tag", func(t *testing.T) {
+		ctx := context.Background()
+		ctrl := gomock.NewController(t)
+
+		m1 := newMockMigration(ctrl, "1")
+		m2 := &metadataMigration{newMockMigration(ctrl, "2"), MigrationMetadata{Tags: []string{"schema"}}}
+		m3 := &metadataMigration{newMockMigration(ctrl, "3"), MigrationMetadata{Tags: []string{"data", "slow"}}}
+		m4 := newMockMigration(ctrl, "4")
+
+		source := NewMockSource(ctrl)
+		target := NewMockTarget(ctrl)
+
+		source.EXPECT().
+			Load(ctx).
+			Return(RepositoryBuilder().WithMigration(m1, m2, m3, m4).Build(), nil)
+		target.EXPECT().
+			Current(ctx).
+			Return("", ErrNoCurrentMigration)
+
+		gotPlan, err := MigratePlannerWithOptions(ExcludeTags("slow"))(source, target).Plan(ctx)
+		require.NoError(t, err)
+		// The migration 4 has no excluded tag, but it is left out, as it would make the migration 3 out of order.
+		require.Len(t, gotPlan, 2)
+		assert.Equal(t, m1, gotPlan[0].Migration)
+		assert.Equal(t, m2, gotPlan[1].Migration)
+	})
+
+	t.Run("should plan the excluded migrations in order in a later run without the option", func(t *testing.T) {
+		ctx := context.Background()
+		ctrl := gomock.NewController(t)
+
+		m1 := newMockMigration(ctrl, "1")
+		m2 := &metadataMigration{newMockMigration(ctrl, "2"), MigrationMetadata{Tags: []string{"slow"}}}
+		m3 := newMockMigration(ctrl, "3")
+
+		source := NewMockSource(ctrl)
+		target := NewMockTarget(ctrl)
+
+		source.EXPECT().
+			Load(ctx).
+			Return(RepositoryBuilder().WithMigration(m1, m2, m3).Build(), nil)
+		target.EXPECT().
+			Current(ctx).
+			Return("1", nil)
+		target.EXPECT().
+			Done(ctx).
+			Return([]string{"1"}, nil).
+			AnyTimes()
+
+		gotPlan, err := MigratePlanner(source, target).Plan(ctx)
+		require.NoError(t, err)
+		require.Len(t, gotPlan, 2)
+		assert.Equal(t, m2, gotPlan[0].Migration)
+		assert.Equal(t, m3, gotPlan[1].Migration)
+		assert.False(t, gotPlan[0].OutOfOrder)
+	})
+}
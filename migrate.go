@@ -2,6 +2,7 @@ package migrations
 
 import (
 	"context"
+	"io"
 )
 
 type ActionPLanner func(source Source, target Target) Planner
@@ -11,6 +12,7 @@ type migrateOptions struct {
 	Reporter      RunnerReporter
 	RunnerOptions []RunnerOption
 	Planner       ActionPLanner
+	DryRun        io.Writer
 }
 
 type MigrateOption func(*migrateOptions)
@@ -33,6 +35,10 @@ func Migrate(ctx context.Context, source Source, target Target, opts ...MigrateO
 
 	runner := options.Runner
 
+	if options.DryRun != nil {
+		return dryRun(ctx, runner, options.Planner, options.DryRun)
+	}
+
 	unlocker, err := runner.target.Lock(ctx)
 	if err != nil {
 		return ExecutionResponse{}, err
@@ -76,3 +82,12 @@ func WithPlanner(planner ActionPLanner) MigrateOption {
 		options.Planner = planner
 	}
 }
+
+// WithDryRun resolves the plan and writes it into w, with the content of each migration, instead of executing it.
+// Neither the migrations nor the Target state are touched: the Target is not locked nor created. A Target that was not
+// created yet is planned as if no migration was applied, when it implements ExistenceChecker.
+func WithDryRun(w io.Writer) MigrateOption {
+	return func(options *migrateOptions) {
+		options.DryRun = w
+	}
+}
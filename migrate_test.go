@@ -0,0 +1,67 @@
+package migrations
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// existenceTarget decorates the MockTarget with the ExistenceChecker implementation.
+type existenceTarget struct {
+	*MockTarget
+	exists bool
+}
+
+func (t *existenceTarget) Exists(_ context.Context) (bool, error) {
+	return t.exists, nil
+}
+
+func TestMigrate_DryRun(t *testing.T) {
+	t.Run("should plan against a target that was not created without locking nor creating it", func(t *testing.T) {
+		ctx := context.Background()
+		ctrl := gomock.NewController(t)
+
+		m1 := newMockMigration(ctrl, "1")
+		m2 := newMockMigration(ctrl, "2")
+
+		source := NewMockSource(ctrl)
+		source.EXPECT().
+			Load(ctx).
+			Return(RepositoryBuilder().WithMigration(m1, m2).Build(), nil)
+
+		// Any call to Lock, Create or Current would fail the test, as none is expected.
+		target := &existenceTarget{MockTarget: NewMockTarget(ctrl)}
+
+		var output bytes.Buffer
+		_, err := Migrate(ctx, source, target, WithDryRun(&output))
+		require.NoError(t, err)
+		assert.Contains(t, output.String(), "-- dry run: 2 actions planned")
+	})
+
+	t.Run("should plan against the migrations applied when the target exists", func(t *testing.T) {
+		ctx := context.Background()
+		ctrl := gomock.NewController(t)
+
+		m1 := newMockMigration(ctrl, "1")
+		m2 := newMockMigration(ctrl, "2")
+
+		source := NewMockSource(ctrl)
+		source.EXPECT().
+			Load(ctx).
+			Return(RepositoryBuilder().WithMigration(m1, m2).Build(), nil)
+
+		target := &existenceTarget{MockTarget: NewMockTarget(ctrl), exists: true}
+		target.EXPECT().Current(ctx).Return("1", nil)
+		target.EXPECT().Done(ctx).Return([]string{"1"}, nil).AnyTimes()
+
+		var output bytes.Buffer
+		_, err := Migrate(ctx, source, target, WithDryRun(&output))
+		require.NoError(t, err)
+		assert.Contains(t, output.String(), "-- dry run: 1 actions planned")
+		assert.Contains(t, output.String(), "do 2")
+	})
+}
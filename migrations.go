@@ -101,6 +101,27 @@ type Target interface {
 	Lock(ctx context.Context) (Unlocker, error)
 }
 
+// TransactionalTarget is an optional interface for a Target that is able to run migrations, and their bookkeeping,
+// inside of a transaction.
+type TransactionalTarget interface {
+	// Transaction runs fn inside of a transaction, that is carried by the context given to fn. The transaction is
+	// committed when fn succeeds and rolled back otherwise. If ctx already carries a transaction, fn runs within it.
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// NonTransactionalMigration is an optional interface for migrations that cannot run inside of a transaction. As an
+// example, `CREATE INDEX CONCURRENTLY` cannot be executed inside of a transaction block in Postgres.
+type NonTransactionalMigration interface {
+	NoTransaction() bool
+}
+
+// TransactionRequiredMigration is an optional interface for migrations that must run inside of a transaction, as the
+// `fnc.TxMigration` that receive the transaction. They are wrapped, with their bookkeeping, into their own transaction
+// even when the Runner uses TransactionModeNone, which requires the Target to implement TransactionalTarget.
+type TransactionRequiredMigration interface {
+	RequiresTransaction() bool
+}
+
 // Unlocker abstracts an implementation for unlocking the migration system.
 type Unlocker interface {
 	Unlock(ctx context.Context) error
@@ -123,4 +144,6 @@ const (
 type Action struct {
 	Action    ActionType
 	Migration Migration
+	// OutOfOrder flags a migration that is older than the current migration, but was not applied yet.
+	OutOfOrder bool
 }
@@ -7,8 +7,49 @@ import (
 )
 
 type migratePlanner struct {
-	source Source
-	target Target
+	source          Source
+	target          Target
+	allowOutOfOrder bool
+	verifyChecksums bool
+	excludeTags     []string
+}
+
+type migratePlannerOptions struct {
+	AllowOutOfOrder bool
+	VerifyChecksums bool
+	ExcludeTags     []string
+}
+
+type MigratePlannerOption func(*migratePlannerOptions)
+
+// AllowOutOfOrder makes the planner apply every migration that is missing from the target, even if it is older than
+// the current migration, instead of failing with ErrStaleMigrationDetected. Migrations applied this way are flagged as
+// out of order in the plan, so they are reported by the RunnerReporter.
+func AllowOutOfOrder() MigratePlannerOption {
+	return func(options *migratePlannerOptions) {
+		options.AllowOutOfOrder = true
+	}
+}
+
+// VerifyChecksums makes the planner verify that the content of every applied migration still matches the checksum
+// recorded when it was applied. If any of them does not, a ChecksumMismatchError is returned. The Target must implement
+// HistoryTarget, otherwise an ErrChecksumNotSupported is returned.
+//
+// Use RepairChecksums to deliberately accept the new content of the migrations.
+func VerifyChecksums() MigratePlannerOption {
+	return func(options *migratePlannerOptions) {
+		options.VerifyChecksums = true
+	}
+}
+
+// ExcludeTags makes the planner stop the plan right before the first migration that has any of the given tags (see
+// MetadataMigration). The migrations after it are left out as well, even when they have none of the tags: skipping the
+// tagged migration would apply the later ones before it, making it out of order (ErrStaleMigrationDetected) for a later
+// run. Stopping lets a later run without this option apply all of them in order.
+func ExcludeTags(tags ...string) MigratePlannerOption {
+	return func(options *migratePlannerOptions) {
+		options.ExcludeTags = append(options.ExcludeTags, tags...)
+	}
 }
 
 // MigratePlanner is an ActionPlanner that returns a Planner that plans actions to take the current version of the
@@ -20,7 +61,39 @@ func MigratePlanner(source Source, target Target) Planner {
 	}
 }
 
+// MigratePlannerWithOptions builds an ActionPlanner that works as the MigratePlanner, but configured by the given
+// options.
+func MigratePlannerWithOptions(opts ...MigratePlannerOption) ActionPLanner {
+	options := migratePlannerOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return func(source Source, target Target) Planner {
+		return &migratePlanner{
+			source:          source,
+			target:          target,
+			allowOutOfOrder: options.AllowOutOfOrder,
+			verifyChecksums: options.VerifyChecksums,
+			excludeTags:     options.ExcludeTags,
+		}
+	}
+}
+
 func (planner *migratePlanner) Plan(ctx context.Context) (Plan, error) {
+	plan, err := planner.plan(ctx)
+	if err != nil || len(planner.excludeTags) == 0 {
+		return plan, err
+	}
+
+	for i, action := range plan {
+		if migrationMetadata(action.Migration).HasTag(planner.excludeTags...) {
+			return plan[:i], nil
+		}
+	}
+	return plan, nil
+}
+
+func (planner *migratePlanner) plan(ctx context.Context) (Plan, error) {
 	repo, err := planner.source.Load(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("%w: error listing available migrations", err)
@@ -58,6 +131,17 @@ func (planner *migratePlanner) Plan(ctx context.Context) (Plan, error) {
 		}
 	}
 
+	if planner.verifyChecksums {
+		err = verifyChecksums(ctx, repo, planner.target)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if planner.allowOutOfOrder {
+		return planOutOfOrder(migrationList, done, currentMigrationID), nil
+	}
+
 	// Detects if a migration was added to the list of applied migrations that is not in the source list.
 	for i, m := range done {
 		if m != migrationList[i].ID() {
@@ -96,3 +180,25 @@ func (planner *migratePlanner) Plan(ctx context.Context) (Plan, error) {
 
 	return plan, nil
 }
+
+// planOutOfOrder plans all migrations that were not applied yet, in ID order. The ones older than the current migration
+// are flagged as out of order.
+func planOutOfOrder(migrationList []Migration, done []string, currentMigrationID string) Plan {
+	applied := make(map[string]struct{}, len(done))
+	for _, migrationID := range done {
+		applied[migrationID] = struct{}{}
+	}
+
+	plan := make(Plan, 0)
+	for _, m := range migrationList {
+		if _, ok := applied[m.ID()]; ok || m.ID() == currentMigrationID {
+			continue
+		}
+		plan = append(plan, &Action{
+			Action:     ActionTypeDo,
+			Migration:  m,
+			OutOfOrder: m.ID() < currentMigrationID,
+		})
+	}
+	return plan
+}
@@ -302,3 +302,88 @@ func Test_migratePlanner_Plan(t *testing.T) {
 		assert.Empty(t, gotPlan)
 	})
 }
+
+func Test_migratePlanner_Plan_AllowOutOfOrder(t *testing.T) {
+	t.Run("should plan the missing migrations flagging the older ones as out of order", func(t *testing.T) {
+		ctx := context.Background()
+
+		ctrl := gomock.NewController(t)
+
+		source := NewMockSource(ctrl)
+		target := NewMockTarget(ctrl)
+
+		m1 := newMockMigration(ctrl, "1")
+		m2 := newMockMigration(ctrl, "2") // missing migration, merged from another branch
+		m3 := newMockMigration(ctrl, "3") // current migration
+		m4 := newMockMigration(ctrl, "4")
+
+		source.EXPECT().
+			Load(ctx).
+			Return(
+				RepositoryBuilder().
+					WithMigration(m1, m2, m3, m4).
+					Build(),
+				nil,
+			)
+
+		target.EXPECT().
+			Current(ctx).
+			Return(m3.ID(), nil)
+
+		target.EXPECT().
+			Done(ctx).
+			Return([]string{
+				m1.ID(),
+				m3.ID(),
+			}, nil)
+
+		planner := MigratePlannerWithOptions(AllowOutOfOrder())(source, target)
+		gotPlan, err := planner.Plan(ctx)
+		require.NoError(t, err)
+
+		require.Len(t, gotPlan, 2)
+		assert.Equal(t, m2, gotPlan[0].Migration)
+		assert.Equal(t, ActionTypeDo, gotPlan[0].Action)
+		assert.True(t, gotPlan[0].OutOfOrder)
+		assert.Equal(t, m4, gotPlan[1].Migration)
+		assert.Equal(t, ActionTypeDo, gotPlan[1].Action)
+		assert.False(t, gotPlan[1].OutOfOrder)
+	})
+
+	t.Run("should fail when a migration is applied but not listed", func(t *testing.T) {
+		ctx := context.Background()
+
+		ctrl := gomock.NewController(t)
+
+		source := NewMockSource(ctrl)
+		target := NewMockTarget(ctrl)
+
+		m1 := newMockMigration(ctrl, "1")
+		m2 := newMockMigration(ctrl, "2")
+
+		source.EXPECT().
+			Load(ctx).
+			Return(
+				RepositoryBuilder().
+					WithMigration(m1).
+					Build(),
+				nil,
+			)
+
+		target.EXPECT().
+			Current(ctx).
+			Return(m2.ID(), nil)
+
+		target.EXPECT().
+			Done(ctx).
+			Return([]string{
+				m1.ID(),
+				m2.ID(),
+			}, nil)
+
+		planner := MigratePlannerWithOptions(AllowOutOfOrder())(source, target)
+		gotPlan, err := planner.Plan(ctx)
+		assert.ErrorIs(t, err, ErrMigrationNotFound)
+		assert.Empty(t, gotPlan)
+	})
+}
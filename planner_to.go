@@ -0,0 +1,103 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+)
+
+type toPlanner struct {
+	source Source
+	target Target
+	id     string
+}
+
+// ToPlanner builds an ActionPlanner that plans the actions needed to take the current version of the database to the
+// migration with the given ID. All applied migrations after it will be undone and all the missing migrations up to,
+// and including, it will be applied.
+//
+// If the migration ID cannot be found in the source, an ErrMigrationNotFound is returned. If any migration that should
+// be undone cannot be undone, an ErrMigrationNotUndoable is returned.
+func ToPlanner(id string) ActionPLanner {
+	return func(source Source, target Target) Planner {
+		return &toPlanner{
+			source: source,
+			target: target,
+			id:     id,
+		}
+	}
+}
+
+func (planner *toPlanner) Plan(ctx context.Context) (Plan, error) {
+	repo, err := planner.source.Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error listing available migrations", err)
+	}
+
+	migrationList, err := repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = repo.ByID(planner.id)
+	if err != nil {
+		return nil, err
+	}
+
+	targetMigrationIndex, err := findMigrationIndexByID(migrationList, planner.id)
+	if err != nil {
+		return nil, err
+	}
+
+	// The plan is built from the applied migrations, instead of the current one, as there may be gaps in them (see
+	// AllowOutOfOrder).
+	done, err := planner.target.Done(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed listing migrations applied: %w", err)
+	}
+	applied := make(map[string]struct{}, len(done))
+	for _, migrationID := range done {
+		_, err := repo.ByID(migrationID)
+		if err != nil {
+			return nil, err
+		}
+		applied[migrationID] = struct{}{}
+	}
+
+	plan := make(Plan, 0)
+
+	// The applied migrations after the target are undone in the inverse execution order.
+	for i := len(migrationList) - 1; i > targetMigrationIndex; i-- {
+		m := migrationList[i]
+		if _, ok := applied[m.ID()]; !ok {
+			continue
+		}
+		if !m.CanUndo() {
+			return nil, WrapMigration(ErrMigrationNotUndoable, m)
+		}
+		plan = append(plan, &Action{
+			Action:    ActionTypeUndo,
+			Migration: m,
+		})
+	}
+
+	// Then, the missing migrations up to the target are applied. The ones older than the most recent applied migration
+	// that is kept are flagged as out of order.
+	var currentMigrationID string
+	for _, m := range migrationList[:targetMigrationIndex+1] {
+		if _, ok := applied[m.ID()]; ok {
+			currentMigrationID = m.ID()
+		}
+	}
+	for _, m := range migrationList[:targetMigrationIndex+1] {
+		if _, ok := applied[m.ID()]; ok {
+			continue
+		}
+		plan = append(plan, &Action{
+			Action:     ActionTypeDo,
+			Migration:  m,
+			OutOfOrder: m.ID() < currentMigrationID,
+		})
+	}
+
+	return plan, nil
+}
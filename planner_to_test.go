@@ -0,0 +1,300 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_toPlanner_Plan(t *testing.T) {
+	t.Run("should migrate from no migrations up to the target", func(t *testing.T) {
+		ctx := context.Background()
+
+		ctrl := gomock.NewController(t)
+
+		m1 := newMockMigration(ctrl, "1")
+		m2 := newMockMigration(ctrl, "2") // target migration
+		m3 := newMockMigration(ctrl, "3")
+
+		source := NewMockSource(ctrl)
+		target := NewMockTarget(ctrl)
+
+		source.EXPECT().
+			Load(ctx).
+			Return(RepositoryBuilder().WithMigration(m1, m2, m3).Build(), nil)
+
+		target.EXPECT().
+			Done(ctx).
+			Return([]string{}, nil)
+
+		gotPlan, err := ToPlanner(m2.ID())(source, target).Plan(ctx)
+		require.NoError(t, err)
+
+		require.Len(t, gotPlan, 2)
+		assert.Equal(t, m1, gotPlan[0].Migration)
+		assert.Equal(t, ActionTypeDo, gotPlan[0].Action)
+		assert.Equal(t, m2, gotPlan[1].Migration)
+		assert.Equal(t, ActionTypeDo, gotPlan[1].Action)
+	})
+
+	t.Run("should migrate from the current migration up to the target", func(t *testing.T) {
+		ctx := context.Background()
+
+		ctrl := gomock.NewController(t)
+
+		m1 := newMockMigration(ctrl, "1") // current migration
+		m2 := newMockMigration(ctrl, "2")
+		m3 := newMockMigration(ctrl, "3") // target migration
+		m4 := newMockMigration(ctrl, "4")
+
+		source := NewMockSource(ctrl)
+		target := NewMockTarget(ctrl)
+
+		source.EXPECT().
+			Load(ctx).
+			Return(RepositoryBuilder().WithMigration(m1, m2, m3, m4).Build(), nil)
+
+		target.EXPECT().
+			Done(ctx).
+			Return([]string{m1.ID()}, nil)
+
+		gotPlan, err := ToPlanner(m3.ID())(source, target).Plan(ctx)
+		require.NoError(t, err)
+
+		require.Len(t, gotPlan, 2)
+		assert.Equal(t, m2, gotPlan[0].Migration)
+		assert.Equal(t, ActionTypeDo, gotPlan[0].Action)
+		assert.Equal(t, m3, gotPlan[1].Migration)
+		assert.Equal(t, ActionTypeDo, gotPlan[1].Action)
+	})
+
+	t.Run("should rewind from the current migration down to the target", func(t *testing.T) {
+		ctx := context.Background()
+
+		ctrl := gomock.NewController(t)
+
+		m1 := newMockMigration(ctrl, "1") // target migration
+		m2 := newMockMigration(ctrl, "2")
+		m3 := newMockMigration(ctrl, "3") // current migration
+
+		m2.EXPECT().CanUndo().Return(true)
+		m3.EXPECT().CanUndo().Return(true)
+
+		source := NewMockSource(ctrl)
+		target := NewMockTarget(ctrl)
+
+		source.EXPECT().
+			Load(ctx).
+			Return(RepositoryBuilder().WithMigration(m1, m2, m3).Build(), nil)
+
+		target.EXPECT().
+			Done(ctx).
+			Return([]string{m1.ID(), m2.ID(), m3.ID()}, nil)
+
+		gotPlan, err := ToPlanner(m1.ID())(source, target).Plan(ctx)
+		require.NoError(t, err)
+
+		require.Len(t, gotPlan, 2)
+		assert.Equal(t, m3, gotPlan[0].Migration)
+		assert.Equal(t, ActionTypeUndo, gotPlan[0].Action)
+		assert.Equal(t, m2, gotPlan[1].Migration)
+		assert.Equal(t, ActionTypeUndo, gotPlan[1].Action)
+	})
+
+	t.Run("should return an empty plan when the current migration is the target", func(t *testing.T) {
+		ctx := context.Background()
+
+		ctrl := gomock.NewController(t)
+
+		m1 := newMockMigration(ctrl, "1")
+		m2 := newMockMigration(ctrl, "2") // current and target migration
+
+		source := NewMockSource(ctrl)
+		target := NewMockTarget(ctrl)
+
+		source.EXPECT().
+			Load(ctx).
+			Return(RepositoryBuilder().WithMigration(m1, m2).Build(), nil)
+
+		target.EXPECT().
+			Done(ctx).
+			Return([]string{m1.ID(), m2.ID()}, nil)
+
+		gotPlan, err := ToPlanner(m2.ID())(source, target).Plan(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, gotPlan)
+	})
+
+	t.Run("should fail when the target migration is not in the source", func(t *testing.T) {
+		ctx := context.Background()
+
+		ctrl := gomock.NewController(t)
+
+		m1 := newMockMigration(ctrl, "1")
+
+		source := NewMockSource(ctrl)
+		target := NewMockTarget(ctrl)
+
+		source.EXPECT().
+			Load(ctx).
+			Return(RepositoryBuilder().WithMigration(m1).Build(), nil)
+
+		gotPlan, err := ToPlanner("2")(source, target).Plan(ctx)
+		assert.ErrorIs(t, err, ErrMigrationNotFound)
+		nErr, ok := err.(MigrationIDError)
+		require.True(t, ok)
+		assert.Equal(t, "2", nErr.MigrationID())
+		assert.Empty(t, gotPlan)
+	})
+
+	t.Run("should fail when a migration in the path cannot be undone", func(t *testing.T) {
+		ctx := context.Background()
+
+		ctrl := gomock.NewController(t)
+
+		m1 := newMockMigration(ctrl, "1") // target migration
+		m2 := newMockMigration(ctrl, "2")
+		m3 := newMockMigration(ctrl, "3") // current migration
+
+		m3.EXPECT().CanUndo().Return(true)
+		m2.EXPECT().CanUndo().Return(false)
+
+		source := NewMockSource(ctrl)
+		target := NewMockTarget(ctrl)
+
+		source.EXPECT().
+			Load(ctx).
+			Return(RepositoryBuilder().WithMigration(m1, m2, m3).Build(), nil)
+
+		target.EXPECT().
+			Done(ctx).
+			Return([]string{m1.ID(), m2.ID(), m3.ID()}, nil)
+
+		gotPlan, err := ToPlanner(m1.ID())(source, target).Plan(ctx)
+		assert.ErrorIs(t, err, ErrMigrationNotUndoable)
+		var mErr MigrationError
+		require.ErrorAs(t, err, &mErr)
+		assert.Equal(t, m2, mErr.Migration())
+		assert.Empty(t, gotPlan)
+	})
+
+	t.Run("should fail when listing the applied migrations fails", func(t *testing.T) {
+		ctx := context.Background()
+
+		ctrl := gomock.NewController(t)
+
+		m1 := newMockMigration(ctrl, "1")
+
+		source := NewMockSource(ctrl)
+		target := NewMockTarget(ctrl)
+
+		wantErr := errors.New("random error")
+
+		source.EXPECT().
+			Load(ctx).
+			Return(RepositoryBuilder().WithMigration(m1).Build(), nil)
+
+		target.EXPECT().
+			Done(ctx).
+			Return(nil, wantErr)
+
+		gotPlan, err := ToPlanner(m1.ID())(source, target).Plan(ctx)
+		assert.ErrorIs(t, err, wantErr)
+		assert.Empty(t, gotPlan)
+	})
+	t.Run("should only undo the applied migrations after the target", func(t *testing.T) {
+		ctx := context.Background()
+
+		ctrl := gomock.NewController(t)
+
+		m1 := newMockMigration(ctrl, "1") // target migration
+		m2 := newMockMigration(ctrl, "2") // merged after 3 was applied
+		m3 := newMockMigration(ctrl, "3")
+
+		m3.EXPECT().CanUndo().Return(true)
+
+		source := NewMockSource(ctrl)
+		target := NewMockTarget(ctrl)
+
+		source.EXPECT().
+			Load(ctx).
+			Return(RepositoryBuilder().WithMigration(m1, m2, m3).Build(), nil)
+
+		target.EXPECT().
+			Done(ctx).
+			Return([]string{m1.ID(), m3.ID()}, nil)
+
+		gotPlan, err := ToPlanner(m1.ID())(source, target).Plan(ctx)
+		require.NoError(t, err)
+
+		require.Len(t, gotPlan, 1)
+		assert.Equal(t, m3, gotPlan[0].Migration)
+		assert.Equal(t, ActionTypeUndo, gotPlan[0].Action)
+	})
+
+	t.Run("should undo the migrations after the target and apply the missing ones up to it", func(t *testing.T) {
+		ctx := context.Background()
+
+		ctrl := gomock.NewController(t)
+
+		m1 := newMockMigration(ctrl, "1")
+		m2 := newMockMigration(ctrl, "2") // target migration, merged after 3 was applied
+		m3 := newMockMigration(ctrl, "3")
+
+		m3.EXPECT().CanUndo().Return(true)
+
+		source := NewMockSource(ctrl)
+		target := NewMockTarget(ctrl)
+
+		source.EXPECT().
+			Load(ctx).
+			Return(RepositoryBuilder().WithMigration(m1, m2, m3).Build(), nil)
+
+		target.EXPECT().
+			Done(ctx).
+			Return([]string{m1.ID(), m3.ID()}, nil)
+
+		gotPlan, err := ToPlanner(m2.ID())(source, target).Plan(ctx)
+		require.NoError(t, err)
+
+		require.Len(t, gotPlan, 2)
+		assert.Equal(t, m3, gotPlan[0].Migration)
+		assert.Equal(t, ActionTypeUndo, gotPlan[0].Action)
+		assert.Equal(t, m2, gotPlan[1].Migration)
+		assert.Equal(t, ActionTypeDo, gotPlan[1].Action)
+		assert.False(t, gotPlan[1].OutOfOrder)
+	})
+
+	t.Run("should flag the missing migrations older than the applied ones as out of order", func(t *testing.T) {
+		ctx := context.Background()
+
+		ctrl := gomock.NewController(t)
+
+		m1 := newMockMigration(ctrl, "1")
+		m2 := newMockMigration(ctrl, "2")
+		m3 := newMockMigration(ctrl, "3") // target migration
+
+		source := NewMockSource(ctrl)
+		target := NewMockTarget(ctrl)
+
+		source.EXPECT().
+			Load(ctx).
+			Return(RepositoryBuilder().WithMigration(m1, m2, m3).Build(), nil)
+
+		target.EXPECT().
+			Done(ctx).
+			Return([]string{m1.ID(), m3.ID()}, nil)
+
+		gotPlan, err := ToPlanner(m3.ID())(source, target).Plan(ctx)
+		require.NoError(t, err)
+
+		require.Len(t, gotPlan, 1)
+		assert.Equal(t, m2, gotPlan[0].Migration)
+		assert.Equal(t, ActionTypeDo, gotPlan[0].Action)
+		assert.True(t, gotPlan[0].OutOfOrder)
+	})
+}
@@ -0,0 +1,181 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+)
+
+// DirtyMigrations lists the migrations that were started but not completed. The target must implement HistoryTarget,
+// otherwise an ErrDirtyNotSupported is returned.
+func DirtyMigrations(ctx context.Context, target Target) ([]HistoryEntry, error) {
+	historyTarget, ok := target.(HistoryTarget)
+	if !ok {
+		return nil, ErrDirtyNotSupported
+	}
+
+	history, err := historyTarget.History(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]HistoryEntry, 0)
+	for _, entry := range history {
+		if entry.Dirty {
+			result = append(result, entry)
+		}
+	}
+	return result, nil
+}
+
+// RecoveryOperation is an operation that recovers from a dirty migration.
+type RecoveryOperation string
+
+const (
+	// RecoveryOperationForce marks the dirty migration as completed (see ForceMigration).
+	RecoveryOperationForce RecoveryOperation = "force"
+	// RecoveryOperationRemove removes the record of the dirty migration (see RemoveDirtyMigration).
+	RecoveryOperationRemove RecoveryOperation = "remove"
+	// RecoveryOperationRetry executes the dirty migration again (see RetryDirtyMigration).
+	RecoveryOperationRetry RecoveryOperation = "retry"
+)
+
+// RecoveryInfo describes an operation executed to recover from a dirty migration.
+type RecoveryInfo struct {
+	Operation   RecoveryOperation
+	MigrationID string
+	// Action is the action executed again by RecoveryOperationRetry.
+	Action ActionType
+	Err    error
+}
+
+// RecoveryReporter is an optional interface for RunnerReporters that audit the operations executed to recover from
+// dirty migrations. As these operations change the migrations applied without executing them as planned, every attempt
+// is reported, failed or not.
+type RecoveryReporter interface {
+	AfterRecovery(ctx context.Context, info *RecoveryInfo)
+}
+
+// ForceMigration marks the dirty migration as completed, without executing it. It should be used when the changes of
+// the migration were fixed manually. The operation is reported to the reporter of the options, when it implements
+// RecoveryReporter.
+//
+// If the migration is not dirty, an ErrMigrationNotDirty is returned. The operation runs while the Target is locked.
+func ForceMigration(ctx context.Context, target Target, id string, opts ...RunnerOption) error {
+	info := RecoveryInfo{Operation: RecoveryOperationForce, MigrationID: id}
+	return recoverDirtyMigration(ctx, target, &info, opts, func() error {
+		return target.FinishMigration(ctx, id)
+	})
+}
+
+// RemoveDirtyMigration removes the dirty migration from the list of the applied migrations, without executing its
+// undo. It should be used when the changes of the migration were reverted manually, so it can be applied again. The
+// operation is reported to the reporter of the options, when it implements RecoveryReporter.
+//
+// If the migration is not dirty, an ErrMigrationNotDirty is returned. The operation runs while the Target is locked.
+func RemoveDirtyMigration(ctx context.Context, target Target, id string, opts ...RunnerOption) error {
+	info := RecoveryInfo{Operation: RecoveryOperationRemove, MigrationID: id}
+	return recoverDirtyMigration(ctx, target, &info, opts, func() error {
+		return target.Remove(ctx, id)
+	})
+}
+
+// RetryDirtyMigration executes the action that left the migration dirty again, using a Runner created with the given
+// options. A migration is left dirty by a failed ActionTypeDo, in which case its record is removed and the migration is
+// applied as if it was never applied, or by a failed ActionTypeUndo, in which case the undo is executed again. If it
+// fails again, it is left dirty. When using transactions, the record is removed in the transaction of the migration,
+// so it is rolled back with it.
+//
+// The operation is reported to the reporter of the options, when it implements RecoveryReporter. If the migration is
+// not dirty, an ErrMigrationNotDirty is returned. The operation runs while the Target is locked.
+func RetryDirtyMigration(ctx context.Context, source Source, target Target, id string, action ActionType, opts ...RunnerOption) (ExecutionResponse, error) {
+	if action != ActionTypeDo && action != ActionTypeUndo {
+		return ExecutionResponse{}, fmt.Errorf("%w: %s", ErrInvalidAction, string(action))
+	}
+
+	var stats ExecutionResponse
+	info := RecoveryInfo{Operation: RecoveryOperationRetry, MigrationID: id, Action: action}
+	err := recoverDirtyMigration(ctx, target, &info, opts, func() error {
+		repo, err := source.Load(ctx)
+		if err != nil {
+			return err
+		}
+
+		m, err := repo.ByID(id)
+		if err != nil {
+			return err
+		}
+
+		runner := NewRunner(source, target, opts...)
+		req := &ExecuteRequest{
+			Plan: Plan{
+				{Action: action, Migration: m},
+			},
+		}
+		// The undo runs over the record left dirty.
+		if action == ActionTypeUndo {
+			stats, err = runner.Execute(ctx, req)
+			return err
+		}
+
+		// The migration is only added again after removing its record. The plan is checked first, so the record is not
+		// removed when the runner cannot execute it.
+		_, err = runner.checkTransactions(req.Plan)
+		if err != nil {
+			return err
+		}
+		retry := func(ctx context.Context) error {
+			err := target.Remove(ctx, id)
+			if err != nil {
+				return err
+			}
+			stats, err = runner.Execute(ctx, req)
+			return err
+		}
+		// When the migration runs in a transaction, the record is removed in it as well, so the record is left dirty,
+		// as it was, when the retry fails and the changes of the first failure are still there.
+		if runner.runsInTransaction(m) {
+			return target.(TransactionalTarget).Transaction(ctx, retry)
+		}
+		return retry(ctx)
+	})
+	return stats, err
+}
+
+// recoverDirtyMigration runs fn through withDirtyMigration, reporting the operation to the reporter of the options when
+// it implements RecoveryReporter.
+func recoverDirtyMigration(ctx context.Context, target Target, info *RecoveryInfo, opts []RunnerOption, fn func() error) error {
+	err := withDirtyMigration(ctx, target, info.MigrationID, fn)
+
+	options := runnerOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if reporter, ok := options.Reporter.(RecoveryReporter); ok {
+		info.Err = err
+		reporter.AfterRecovery(ctx, info)
+	}
+	return err
+}
+
+// withDirtyMigration locks the target and, after checking the migration is dirty, calls fn.
+func withDirtyMigration(ctx context.Context, target Target, id string, fn func() error) error {
+	unlocker, err := target.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = unlocker.Unlock(detach(ctx))
+	}()
+
+	dirty, err := DirtyMigrations(ctx, target)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range dirty {
+		if entry.ID == id {
+			return fn()
+		}
+	}
+	return WrapMigrationID(ErrMigrationNotDirty, id)
+}
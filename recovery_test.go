@@ -0,0 +1,254 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// recoveryReporter decorates the MockRunnerReporter with the RecoveryReporter implementation.
+type recoveryReporter struct {
+	*MockRunnerReporter
+	recoveries []RecoveryInfo
+}
+
+func (r *recoveryReporter) AfterRecovery(_ context.Context, info *RecoveryInfo) {
+	r.recoveries = append(r.recoveries, *info)
+}
+
+func newDirtyTarget(ctrl *gomock.Controller) *historyTarget {
+	target := &historyTarget{
+		MockTarget: NewMockTarget(ctrl),
+		history: []HistoryEntry{
+			{ID: "1"},
+			{ID: "2", Dirty: true},
+		},
+	}
+	target.EXPECT().Lock(gomock.Any()).Return(noopUnlocker{}, nil).AnyTimes()
+	return target
+}
+
+// transactionalHistoryTarget decorates the historyTarget with the TransactionalTarget implementation, tracking whether
+// the calls run in a transaction and whether it was rolled back. Nested transactions join the outer one.
+type transactionalHistoryTarget struct {
+	*historyTarget
+	inTransaction bool
+	rolledBack    bool
+}
+
+func (t *transactionalHistoryTarget) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if t.inTransaction {
+		return fn(ctx)
+	}
+	t.inTransaction = true
+	defer func() {
+		t.inTransaction = false
+	}()
+	err := fn(ctx)
+	t.rolledBack = err != nil
+	return err
+}
+
+func TestDirtyMigrations(t *testing.T) {
+	t.Run("should list the dirty migrations", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		got, err := DirtyMigrations(context.Background(), newDirtyTarget(ctrl))
+		require.NoError(t, err)
+		assert.Equal(t, []HistoryEntry{{ID: "2", Dirty: true}}, got)
+	})
+
+	t.Run("should fail when the target does not support listing dirty migrations", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		_, err := DirtyMigrations(context.Background(), NewMockTarget(ctrl))
+		require.ErrorIs(t, err, ErrDirtyNotSupported)
+	})
+}
+
+func TestForceMigration(t *testing.T) {
+	t.Run("should mark the dirty migration as completed", func(t *testing.T) {
+		ctx := context.Background()
+		ctrl := gomock.NewController(t)
+
+		target := newDirtyTarget(ctrl)
+		target.EXPECT().FinishMigration(ctx, "2").Return(nil)
+
+		require.NoError(t, ForceMigration(ctx, target, "2"))
+	})
+
+	t.Run("should report the operation", func(t *testing.T) {
+		ctx := context.Background()
+		ctrl := gomock.NewController(t)
+
+		target := newDirtyTarget(ctrl)
+		target.EXPECT().FinishMigration(ctx, "2").Return(nil)
+
+		reporter := &recoveryReporter{MockRunnerReporter: NewMockRunnerReporter(ctrl)}
+		require.NoError(t, ForceMigration(ctx, target, "2", WithReporter(reporter)))
+		assert.Equal(t, []RecoveryInfo{{Operation: RecoveryOperationForce, MigrationID: "2"}}, reporter.recoveries)
+	})
+
+	t.Run("should fail when the migration is not dirty", func(t *testing.T) {
+		ctx := context.Background()
+		ctrl := gomock.NewController(t)
+
+		err := ForceMigration(ctx, newDirtyTarget(ctrl), "1")
+		require.ErrorIs(t, err, ErrMigrationNotDirty)
+		nErr, ok := err.(MigrationIDError)
+		require.True(t, ok)
+		assert.Equal(t, "1", nErr.MigrationID())
+	})
+}
+
+func TestRemoveDirtyMigration(t *testing.T) {
+	t.Run("should remove the dirty migration", func(t *testing.T) {
+		ctx := context.Background()
+		ctrl := gomock.NewController(t)
+
+		target := newDirtyTarget(ctrl)
+		target.EXPECT().Remove(ctx, "2").Return(nil)
+
+		require.NoError(t, RemoveDirtyMigration(ctx, target, "2"))
+	})
+
+	t.Run("should fail when the migration is not dirty", func(t *testing.T) {
+		ctx := context.Background()
+		ctrl := gomock.NewController(t)
+
+		require.ErrorIs(t, RemoveDirtyMigration(ctx, newDirtyTarget(ctrl), "3"), ErrMigrationNotDirty)
+	})
+
+	t.Run("should report the failed operation", func(t *testing.T) {
+		ctx := context.Background()
+		ctrl := gomock.NewController(t)
+
+		reporter := &recoveryReporter{MockRunnerReporter: NewMockRunnerReporter(ctrl)}
+		err := RemoveDirtyMigration(ctx, newDirtyTarget(ctrl), "3", WithReporter(reporter))
+		require.ErrorIs(t, err, ErrMigrationNotDirty)
+		require.Len(t, reporter.recoveries, 1)
+		assert.Equal(t, RecoveryOperationRemove, reporter.recoveries[0].Operation)
+		assert.ErrorIs(t, reporter.recoveries[0].Err, ErrMigrationNotDirty)
+	})
+}
+
+func TestRetryDirtyMigration(t *testing.T) {
+	t.Run("should execute the dirty migration again", func(t *testing.T) {
+		ctx := context.Background()
+		ctrl := gomock.NewController(t)
+
+		m1 := newMockMigration(ctrl, "1")
+		m2 := newMockMigration(ctrl, "2")
+
+		source := NewMockSource(ctrl)
+		source.EXPECT().
+			Load(ctx).
+			Return(RepositoryBuilder().WithMigration(m1, m2).Build(), nil)
+
+		target := newDirtyTarget(ctrl)
+		gomock.InOrder(
+			target.EXPECT().Remove(ctx, "2").Return(nil),
+			target.EXPECT().Add(ctx, "2").Return(nil),
+			m2.EXPECT().Do(ctx).Return(nil),
+			target.EXPECT().FinishMigration(ctx, "2").Return(nil),
+		)
+
+		stats, err := RetryDirtyMigration(ctx, source, target, "2", ActionTypeDo)
+		require.NoError(t, err)
+		require.Len(t, stats.Successful, 1)
+		assert.Equal(t, m2, stats.Successful[0].Migration)
+	})
+
+	t.Run("should execute the undo of the dirty migration again", func(t *testing.T) {
+		ctx := context.Background()
+		ctrl := gomock.NewController(t)
+
+		m1 := newMockMigration(ctrl, "1")
+		m2 := newMockMigration(ctrl, "2")
+		m2.EXPECT().CanUndo().Return(true).AnyTimes()
+
+		source := NewMockSource(ctrl)
+		source.EXPECT().
+			Load(ctx).
+			Return(RepositoryBuilder().WithMigration(m1, m2).Build(), nil)
+
+		// The record is kept, as it is removed by the undo itself.
+		target := newDirtyTarget(ctrl)
+		gomock.InOrder(
+			target.EXPECT().StartMigration(ctx, "2").Return(nil),
+			m2.EXPECT().Undo(ctx).Return(nil),
+			target.EXPECT().Remove(ctx, "2").Return(nil),
+		)
+
+		stats, err := RetryDirtyMigration(ctx, source, target, "2", ActionTypeUndo)
+		require.NoError(t, err)
+		require.Len(t, stats.Successful, 1)
+		assert.Equal(t, ActionTypeUndo, stats.Successful[0].Action)
+	})
+
+	t.Run("should fail with an invalid action", func(t *testing.T) {
+		ctx := context.Background()
+		ctrl := gomock.NewController(t)
+
+		_, err := RetryDirtyMigration(ctx, NewMockSource(ctrl), newDirtyTarget(ctrl), "2", ActionType("redo"))
+		require.ErrorIs(t, err, ErrInvalidAction)
+	})
+
+	t.Run("should fail when the migration is not in the source", func(t *testing.T) {
+		ctx := context.Background()
+		ctrl := gomock.NewController(t)
+
+		source := NewMockSource(ctrl)
+		source.EXPECT().
+			Load(ctx).
+			Return(RepositoryBuilder().WithMigration(newMockMigration(ctrl, "1")).Build(), nil)
+
+		_, err := RetryDirtyMigration(ctx, source, newDirtyTarget(ctrl), "2", ActionTypeDo)
+		require.ErrorIs(t, err, ErrMigrationNotFound)
+	})
+	t.Run("should remove the record in the transaction of the migration", func(t *testing.T) {
+		ctx := context.Background()
+		ctrl := gomock.NewController(t)
+		wantErr := errors.New("random error")
+
+		m1 := newMockMigration(ctrl, "1")
+		m2 := newMockMigration(ctrl, "2")
+
+		source := NewMockSource(ctrl)
+		source.EXPECT().
+			Load(ctx).
+			Return(RepositoryBuilder().WithMigration(m1, m2).Build(), nil)
+
+		target := &transactionalHistoryTarget{historyTarget: newDirtyTarget(ctrl)}
+		gomock.InOrder(
+			target.EXPECT().Remove(ctx, "2").DoAndReturn(func(context.Context, string) error {
+				assert.True(t, target.inTransaction, "the record should be removed in the transaction")
+				return nil
+			}),
+			target.EXPECT().Add(ctx, "2").Return(nil),
+			m2.EXPECT().Do(ctx).Return(wantErr),
+		)
+
+		_, err := RetryDirtyMigration(ctx, source, target, "2", ActionTypeDo, WithTransactionMode(TransactionModeMigration))
+		require.ErrorIs(t, err, wantErr)
+		// Rolling back the removal leaves the record dirty, as it was.
+		assert.True(t, target.rolledBack)
+	})
+
+	t.Run("should not remove the record when the runner cannot execute the migration", func(t *testing.T) {
+		ctx := context.Background()
+		ctrl := gomock.NewController(t)
+
+		source := NewMockSource(ctrl)
+		source.EXPECT().
+			Load(ctx).
+			Return(RepositoryBuilder().WithMigration(newMockMigration(ctrl, "1"), newMockMigration(ctrl, "2")).Build(), nil)
+
+		_, err := RetryDirtyMigration(ctx, source, newDirtyTarget(ctrl), "2", ActionTypeDo, WithTransactionMode(TransactionModeMigration))
+		require.ErrorIs(t, err, ErrTransactionNotSupported)
+	})
+}
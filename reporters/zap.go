@@ -26,6 +26,10 @@ func (r *runnerReporter) BeforeExecute(_ context.Context, req *migrations.Before
 	}
 	ms := make([]string, len(req.Plan))
 	for i, action := range req.Plan {
+		if action.OutOfOrder {
+			ms[i] = fmt.Sprintf("%s (%s, out of order)", action.Migration.String(), action.Action)
+			continue
+		}
 		ms[i] = fmt.Sprintf("%s (%s)", action.Migration.String(), action.Action)
 	}
 	r.logger.Info(fmt.Sprintf("migration plan with %d migrations", len(req.Plan)), zap.Strings("plan", ms))
@@ -36,7 +40,10 @@ func (r *runnerReporter) BeforeExecuteMigration(_ context.Context, req *migratio
 }
 
 func (r *runnerReporter) AfterExecuteMigration(_ context.Context, req *migrations.AfterExecuteMigrationInfo) {
-	if req.Err == nil {
+	if req.Err == nil && req.OutOfOrder {
+		r.logger.Warn(fmt.Sprintf("migration %s (%s) successfully applied out of order", req.Migration.String(), req.ActionType))
+		return
+	} else if req.Err == nil {
 		r.logger.Info(fmt.Sprintf("migration %s (%s) successfully applied", req.Migration.String(), req.ActionType))
 		return
 	}
@@ -56,3 +63,16 @@ func (r *runnerReporter) AfterExecute(_ context.Context, req *migrations.AfterEx
 		r.logger.Error(fmt.Sprintf("migration %s (%s) failed to be applied", action.Migration.String(), action.Action))
 	}
 }
+
+// AfterRecovery logs the operations executed to recover from dirty migrations, as a warning, for auditing them.
+func (r *runnerReporter) AfterRecovery(_ context.Context, req *migrations.RecoveryInfo) {
+	fields := []zap.Field{zap.String("operation", string(req.Operation)), zap.String("migration", req.MigrationID)}
+	if req.Action != "" {
+		fields = append(fields, zap.String("action", string(req.Action)))
+	}
+	if req.Err != nil {
+		r.logger.Error(fmt.Sprintf("recovery of the dirty migration %s (%s) has failed", req.MigrationID, req.Operation), append(fields, zap.Error(req.Err))...)
+		return
+	}
+	r.logger.Warn(fmt.Sprintf("dirty migration %s recovered (%s)", req.MigrationID, req.Operation), fields...)
+}
@@ -3,17 +3,39 @@ package migrations
 import (
 	"context"
 	"fmt"
+	"io"
+	"time"
 )
 
 // Runner will receive the `Plan` from the `Planner` and execute it.
 type Runner struct {
-	reporter RunnerReporter
-	source   Source
-	target   Target
+	reporter        RunnerReporter
+	source          Source
+	target          Target
+	transactionMode TransactionMode
+	// dependencies add the dependencies provided by WithDependency to the context of the execution.
+	dependencies []func(ctx context.Context) context.Context
 }
 
+// TransactionMode defines how the Runner wraps the execution of a plan into transactions. Any mode other than
+// TransactionModeNone requires the Target to implement TransactionalTarget.
+type TransactionMode string
+
+const (
+	// TransactionModeNone does not use transactions. This is the default mode.
+	TransactionModeNone TransactionMode = ""
+	// TransactionModeMigration wraps each migration, and its bookkeeping, into its own transaction. Migrations
+	// implementing NonTransactionalMigration are executed outside of a transaction.
+	TransactionModeMigration TransactionMode = "migration"
+	// TransactionModePlan wraps the whole plan into a single transaction. If any migration of the plan implements
+	// NonTransactionalMigration, the plan is not executed.
+	TransactionModePlan TransactionMode = "plan"
+)
+
 type runnerOptions struct {
-	Reporter RunnerReporter
+	Reporter        RunnerReporter
+	TransactionMode TransactionMode
+	Dependencies    []func(ctx context.Context) context.Context
 }
 
 type RunnerOption func(*runnerOptions)
@@ -24,9 +46,11 @@ func NewRunner(source Source, target Target, options ...RunnerOption) *Runner {
 		opt(&opts)
 	}
 	return &Runner{
-		source:   source,
-		target:   target,
-		reporter: opts.Reporter,
+		source:          source,
+		target:          target,
+		reporter:        opts.Reporter,
+		transactionMode: opts.TransactionMode,
+		dependencies:    opts.Dependencies,
 	}
 }
 
@@ -36,6 +60,13 @@ func WithReporter(reporter RunnerReporter) RunnerOption {
 	}
 }
 
+// WithTransactionMode sets how the runner uses transactions while executing a plan. Default is TransactionModeNone.
+func WithTransactionMode(mode TransactionMode) RunnerOption {
+	return func(options *runnerOptions) {
+		options.TransactionMode = mode
+	}
+}
+
 type BeforeExecuteInfo struct {
 	Plan Plan
 }
@@ -43,11 +74,13 @@ type BeforeExecuteInfo struct {
 type BeforeExecuteMigrationInfo struct {
 	ActionType ActionType
 	Migration  Migration
+	OutOfOrder bool
 }
 
 type AfterExecuteMigrationInfo struct {
 	ActionType ActionType
 	Migration  Migration
+	OutOfOrder bool
 	Err        error
 }
 
@@ -71,20 +104,30 @@ type ExecutionResponse struct {
 
 type ExecuteRequest struct {
 	Plan Plan
+	// DryRun, when set, makes the runner write the plan, with the content of each migration, into it instead of
+	// executing the plan. Neither the migrations nor the Target are touched.
+	DryRun io.Writer
 }
 
 // Execute performs a plan, running all actions migration by migration.
 //
 // Before running, Execute will check for Undo actions that cannot be performed into undoable migrations. If that
-// happens, an `ErrMigrationNotUndoable` will be returned and nothing will be executed.
+// happens, an `ErrMigrationNotUndoable` will be returned and nothing will be executed. The same happens, with an
+// `ErrRequiredMigrationNotApplied`, when a migration is planned before the migrations it requires (see
+// MetadataMigration).
+//
+// Migrations that declare a timeout are executed with a context limited by it.
 //
 // For each migration executed, the system will move the cursor to that point. So that, if any error happens during the
 // migration execution (do or undo), the execution will be stopped and the error will be returned. All performed actions
-// WILL NOT be rolled back.
+// WILL NOT be rolled back, unless the runner was created using the TransactionModePlan.
 func (runner *Runner) Execute(ctx context.Context, req *ExecuteRequest) (ExecutionResponse, error) {
 	stats := ExecutionResponse{
 		Successful: make([]*Action, 0, len(req.Plan)),
 	}
+	for _, withDependency := range runner.dependencies {
+		ctx = withDependency(ctx)
+	}
 
 	// Check for undoable migrations...
 	for _, action := range req.Plan {
@@ -93,72 +136,195 @@ func (runner *Runner) Execute(ctx context.Context, req *ExecuteRequest) (Executi
 		}
 	}
 
+	txTarget, err := runner.checkTransactions(req.Plan)
+	if err != nil {
+		return stats, err
+	}
+
+	err = runner.checkRequirements(ctx, req.Plan)
+	if err != nil {
+		return stats, err
+	}
+
+	if req.DryRun != nil {
+		return stats, writeDryRun(req.DryRun, req.Plan)
+	}
+
 	if runner.reporter != nil {
 		runner.reporter.BeforeExecute(ctx, &BeforeExecuteInfo{
 			Plan: req.Plan,
 		})
 	}
 
-	for _, action := range req.Plan {
-		var err error
+	if runner.transactionMode == TransactionModePlan {
+		err = txTarget.Transaction(ctx, func(ctx context.Context) error {
+			return runner.executePlan(ctx, req.Plan, &stats)
+		})
+		if err != nil {
+			// The whole plan was rolled back, so nothing was applied.
+			stats.Successful = stats.Successful[:0]
+		}
+	} else {
+		err = runner.executePlan(ctx, req.Plan, &stats)
+	}
+
+	if runner.reporter != nil {
+		runner.reporter.AfterExecute(ctx, &AfterExecuteInfo{
+			Plan:  req.Plan,
+			Stats: &stats,
+			Err:   err,
+		})
+	}
+	return stats, err
+}
+
+// checkTransactions ensures the plan can be executed using the transaction mode of the runner.
+func (runner *Runner) checkTransactions(plan Plan) (TransactionalTarget, error) {
+	txTarget, ok := runner.target.(TransactionalTarget)
+	if runner.transactionMode == TransactionModeNone {
+		// Migrations that require a transaction still get their own.
+		for _, action := range plan {
+			if requiresTransaction(action.Migration) && !ok {
+				return nil, WrapMigration(ErrTransactionNotSupported, action.Migration)
+			}
+		}
+		return nil, nil
+	}
+
+	if !ok {
+		return nil, ErrTransactionNotSupported
+	}
+
+	if runner.transactionMode == TransactionModePlan {
+		for _, action := range plan {
+			if !isTransactional(action.Migration) {
+				return nil, WrapMigration(ErrNonTransactionalMigration, action.Migration)
+			}
+		}
+	}
+	return txTarget, nil
+}
+
+func (runner *Runner) executePlan(ctx context.Context, plan Plan, stats *ExecutionResponse) error {
+	for _, action := range plan {
 		if runner.reporter != nil {
 			runner.reporter.BeforeExecuteMigration(ctx, &BeforeExecuteMigrationInfo{
 				ActionType: action.Action,
 				Migration:  action.Migration,
+				OutOfOrder: action.OutOfOrder,
 			})
 		}
-		switch action.Action {
-		case ActionTypeDo:
-			err = runner.target.Add(ctx, action.Migration.ID())
-			if err != nil {
-				return stats, err
-			}
-			err = action.Migration.Do(ctx)
-			if err == nil {
-				err = runner.target.FinishMigration(ctx, action.Migration.ID())
-			}
-		case ActionTypeUndo:
-			// Undoable migrations were already checked before.
-			err = runner.target.StartMigration(ctx, action.Migration.ID())
-			if err != nil {
-				return ExecutionResponse{}, err
-			}
-			err = action.Migration.Undo(ctx)
-			if err == nil {
-				err = runner.target.Remove(ctx, action.Migration.ID())
-			}
-		default:
-			err = fmt.Errorf("%w: %s", ErrInvalidAction, string(action.Action))
+		started, err := runner.executeAction(ctx, action)
+		if !started {
+			return err
 		}
 		if runner.reporter != nil {
 			runner.reporter.AfterExecuteMigration(ctx, &AfterExecuteMigrationInfo{
 				ActionType: action.Action,
 				Migration:  action.Migration,
+				OutOfOrder: action.OutOfOrder,
 				Err:        err,
 			})
 		}
-		if err == nil {
-			stats.Successful = append(stats.Successful, action)
-		} else {
+		if err != nil {
 			stats.Errored = []*Action{action}
+			return err
+		}
+		stats.Successful = append(stats.Successful, action)
+	}
+	return nil
+}
 
-			if runner.reporter != nil {
-				runner.reporter.AfterExecute(ctx, &AfterExecuteInfo{
-					Plan:  req.Plan,
-					Stats: &stats,
-					Err:   err,
-				})
-			}
+// executeAction runs the action, wrapping it into a transaction when the runner is set to TransactionModeMigration, or
+// when the migration requires one (see TransactionRequiredMigration). The returned flag reports whether the action was
+// started, a failure before that is not accounted as an errored action.
+func (runner *Runner) executeAction(ctx context.Context, action *Action) (started bool, err error) {
+	if !runner.ownTransaction(action.Migration) {
+		return runner.runAction(ctx, action)
+	}
 
-			return stats, err
+	err = runner.target.(TransactionalTarget).Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		started, err = runner.runAction(ctx, action)
+		return err
+	})
+	return started, err
+}
+
+// ownTransaction reports whether the migration is executed in its own transaction.
+func (runner *Runner) ownTransaction(migration Migration) bool {
+	return (runner.transactionMode == TransactionModeMigration && isTransactional(migration)) ||
+		(runner.transactionMode == TransactionModeNone && requiresTransaction(migration))
+}
+
+// runsInTransaction reports whether the migration is executed in a transaction, of its own or of the whole plan.
+func (runner *Runner) runsInTransaction(migration Migration) bool {
+	return runner.transactionMode == TransactionModePlan || runner.ownTransaction(migration)
+}
+
+func (runner *Runner) runAction(ctx context.Context, action *Action) (bool, error) {
+	var err error
+	switch action.Action {
+	case ActionTypeDo:
+		err = runner.target.Add(ctx, action.Migration.ID())
+		if err != nil {
+			return false, err
+		}
+		startedAt := time.Now()
+		err = runner.runMigration(ctx, action.Migration, action.Migration.Do)
+		if err == nil {
+			err = runner.finishMigration(ctx, action.Migration, time.Since(startedAt))
+		}
+	case ActionTypeUndo:
+		// Undoable migrations were already checked before.
+		err = runner.target.StartMigration(ctx, action.Migration.ID())
+		if err != nil {
+			return false, err
 		}
+		err = runner.runMigration(ctx, action.Migration, action.Migration.Undo)
+		if err == nil {
+			err = runner.target.Remove(ctx, action.Migration.ID())
+		}
+	default:
+		err = fmt.Errorf("%w: %s", ErrInvalidAction, string(action.Action))
 	}
-	if runner.reporter != nil {
-		runner.reporter.AfterExecute(ctx, &AfterExecuteInfo{
-			Plan:  req.Plan,
-			Stats: &stats,
-			Err:   nil,
-		})
+	return true, err
+}
+
+// runMigration runs the Do or Undo of the migration, limited by the timeout of the migration, if any.
+func (runner *Runner) runMigration(ctx context.Context, migration Migration, fn func(ctx context.Context) error) error {
+	ctx, cancel := withMigrationTimeout(ctx, migration)
+	defer cancel()
+	return fn(ctx)
+}
+
+// finishMigration marks the migration as finished, recording the details of its execution when the target implements
+// MigrationRecorder.
+func (runner *Runner) finishMigration(ctx context.Context, migration Migration, executionTime time.Duration) error {
+	recorder, ok := runner.target.(MigrationRecorder)
+	if !ok {
+		return runner.target.FinishMigration(ctx, migration.ID())
+	}
+
+	var checksum string
+	if m, ok := migration.(ChecksummedMigration); ok {
+		checksum = m.Checksum()
 	}
-	return stats, nil
+	return recorder.RecordMigration(ctx, HistoryEntry{
+		ID:            migration.ID(),
+		Description:   migration.Description(),
+		Checksum:      checksum,
+		AppliedAt:     time.Now().UTC(),
+		ExecutionTime: executionTime,
+	})
+}
+
+func isTransactional(migration Migration) bool {
+	m, ok := migration.(NonTransactionalMigration)
+	return !ok || !m.NoTransaction()
+}
+
+func requiresTransaction(migration Migration) bool {
+	m, ok := migration.(TransactionRequiredMigration)
+	return ok && m.RequiresTransaction()
 }
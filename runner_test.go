@@ -1,6 +1,7 @@
 package migrations
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"testing"
@@ -332,3 +333,274 @@ func TestRunner_Execute(t *testing.T) {
 		require.Len(t, stats.Errored, 1)
 	})
 }
+
+// transactionalTarget decorates the MockTarget with the TransactionalTarget implementation, counting the
+// transactions started.
+type transactionalTarget struct {
+	*MockTarget
+	transactions int
+}
+
+func (t *transactionalTarget) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	t.transactions++
+	return fn(ctx)
+}
+
+type nonTransactionalMigration struct {
+	*MockMigration
+}
+
+func (m *nonTransactionalMigration) NoTransaction() bool {
+	return true
+}
+
+type txRequiredMigration struct {
+	*MockMigration
+}
+
+func (m *txRequiredMigration) RequiresTransaction() bool {
+	return true
+}
+
+func TestRunner_Execute_Transactions(t *testing.T) {
+	t.Run("should execute each migration in its own transaction", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ctx := context.Background()
+
+		target := &transactionalTarget{MockTarget: NewMockTarget(ctrl)}
+		runner := NewRunner(NewMockSource(ctrl), target, WithTransactionMode(TransactionModeMigration))
+
+		m1 := newMockMigration(ctrl, "1")
+		m2 := &nonTransactionalMigration{newMockMigration(ctrl, "2")}
+		m3 := newMockMigration(ctrl, "3")
+
+		for _, m := range []Migration{m1, m2, m3} {
+			target.EXPECT().Add(ctx, m.ID()).Return(nil)
+			target.EXPECT().FinishMigration(ctx, m.ID()).Return(nil)
+		}
+		m1.EXPECT().Do(ctx).Return(nil)
+		m2.EXPECT().Do(ctx).Return(nil)
+		m3.EXPECT().Do(ctx).Return(nil)
+
+		stats, err := runner.Execute(ctx, &ExecuteRequest{
+			Plan: Plan{
+				{Action: ActionTypeDo, Migration: m1},
+				{Action: ActionTypeDo, Migration: m2},
+				{Action: ActionTypeDo, Migration: m3},
+			},
+		})
+		require.NoError(t, err)
+		assert.Len(t, stats.Successful, 3)
+		assert.Equal(t, 2, target.transactions)
+	})
+
+	t.Run("should execute the whole plan in a single transaction", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ctx := context.Background()
+
+		target := &transactionalTarget{MockTarget: NewMockTarget(ctrl)}
+		runner := NewRunner(NewMockSource(ctrl), target, WithTransactionMode(TransactionModePlan))
+
+		m1 := newMockMigration(ctrl, "1")
+		m2 := newMockMigration(ctrl, "2")
+
+		wantErr := errors.New("random error")
+
+		target.EXPECT().Add(ctx, m1.ID()).Return(nil)
+		target.EXPECT().FinishMigration(ctx, m1.ID()).Return(nil)
+		target.EXPECT().Add(ctx, m2.ID()).Return(nil)
+		m1.EXPECT().Do(ctx).Return(nil)
+		m2.EXPECT().Do(ctx).Return(wantErr)
+
+		stats, err := runner.Execute(ctx, &ExecuteRequest{
+			Plan: Plan{
+				{Action: ActionTypeDo, Migration: m1},
+				{Action: ActionTypeDo, Migration: m2},
+			},
+		})
+		require.ErrorIs(t, err, wantErr)
+		assert.Empty(t, stats.Successful)
+		assert.Len(t, stats.Errored, 1)
+		assert.Equal(t, 1, target.transactions)
+	})
+
+	t.Run("should fail when the plan has a migration that cannot run in a transaction", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ctx := context.Background()
+
+		target := &transactionalTarget{MockTarget: NewMockTarget(ctrl)}
+		runner := NewRunner(NewMockSource(ctrl), target, WithTransactionMode(TransactionModePlan))
+
+		m1 := &nonTransactionalMigration{newMockMigration(ctrl, "1")}
+
+		_, err := runner.Execute(ctx, &ExecuteRequest{
+			Plan: Plan{
+				{Action: ActionTypeDo, Migration: m1},
+			},
+		})
+		require.ErrorIs(t, err, ErrNonTransactionalMigration)
+		assert.Equal(t, 0, target.transactions)
+	})
+
+	t.Run("should fail when the target does not support transactions", func(t *testing.T) {
+		ctx := context.Background()
+		s := createRunner(t)
+		runner := NewRunner(s.source, s.target, WithTransactionMode(TransactionModeMigration))
+
+		_, err := runner.Execute(ctx, &ExecuteRequest{
+			Plan: Plan{
+				{Action: ActionTypeDo, Migration: newMockMigration(s.ctrl, "1")},
+			},
+		})
+		require.ErrorIs(t, err, ErrTransactionNotSupported)
+	})
+
+	t.Run("should wrap the migrations that require a transaction even without a transaction mode", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ctx := context.Background()
+
+		target := &transactionalTarget{MockTarget: NewMockTarget(ctrl)}
+		runner := NewRunner(NewMockSource(ctrl), target)
+
+		m1 := newMockMigration(ctrl, "1")
+		m2 := &txRequiredMigration{newMockMigration(ctrl, "2")}
+
+		for _, m := range []Migration{m1, m2} {
+			target.EXPECT().Add(ctx, m.ID()).Return(nil)
+			target.EXPECT().FinishMigration(ctx, m.ID()).Return(nil)
+		}
+		m1.EXPECT().Do(ctx).Return(nil)
+		m2.EXPECT().Do(ctx).Return(nil)
+
+		stats, err := runner.Execute(ctx, &ExecuteRequest{
+			Plan: Plan{
+				{Action: ActionTypeDo, Migration: m1},
+				{Action: ActionTypeDo, Migration: m2},
+			},
+		})
+		require.NoError(t, err)
+		assert.Len(t, stats.Successful, 2)
+		assert.Equal(t, 1, target.transactions)
+	})
+
+	t.Run("should fail when a migration requires a transaction that the target does not support", func(t *testing.T) {
+		ctx := context.Background()
+		s := createRunner(t)
+		m1 := &txRequiredMigration{newMockMigration(s.ctrl, "1")}
+
+		_, err := s.runner.Execute(ctx, &ExecuteRequest{
+			Plan: Plan{
+				{Action: ActionTypeDo, Migration: m1},
+			},
+		})
+		require.ErrorIs(t, err, ErrTransactionNotSupported)
+		var migrationErr MigrationError
+		require.ErrorAs(t, err, &migrationErr)
+		assert.Equal(t, m1, migrationErr.Migration())
+	})
+}
+
+type scriptedMigration struct {
+	*MockMigration
+}
+
+func (m *scriptedMigration) Script(action ActionType) string {
+	return "-- " + string(action) + " " + m.ID() + "\nSELECT 1;\n"
+}
+
+func TestRunner_Execute_DryRun(t *testing.T) {
+	t.Run("should write the plan without executing it", func(t *testing.T) {
+		ctx := context.Background()
+		s := createRunner(t)
+
+		m1 := &scriptedMigration{newMockMigration(s.ctrl, "1")}
+		m2 := newMockMigration(s.ctrl, "2")
+
+		var output bytes.Buffer
+		stats, err := s.runner.Execute(ctx, &ExecuteRequest{
+			Plan: Plan{
+				{Action: ActionTypeDo, Migration: m1},
+				{Action: ActionTypeDo, Migration: m2, OutOfOrder: true},
+			},
+			DryRun: &output,
+		})
+		require.NoError(t, err)
+		assert.Empty(t, stats.Successful)
+		assert.Empty(t, stats.Errored)
+		assert.Equal(t, `-- dry run: 2 actions planned
+
+-- [1/2] do 1 (migration 1)
+-- do 1
+SELECT 1;
+
+-- [2/2] do 2 (migration 2, out of order)
+-- code migration, its content cannot be displayed
+`, output.String())
+	})
+
+	t.Run("should fail when trying to undo an undoable migration", func(t *testing.T) {
+		ctx := context.Background()
+		s := createRunner(t)
+
+		m1 := newMockMigration(s.ctrl, "1")
+		m1.EXPECT().CanUndo().Return(false)
+
+		var output bytes.Buffer
+		_, err := s.runner.Execute(ctx, &ExecuteRequest{
+			Plan: Plan{
+				{Action: ActionTypeUndo, Migration: m1},
+			},
+			DryRun: &output,
+		})
+		require.ErrorIs(t, err, ErrMigrationNotUndoable)
+		assert.Empty(t, output.String())
+	})
+}
+
+// recorderTarget decorates the MockTarget with the MigrationRecorder implementation.
+type recorderTarget struct {
+	*MockTarget
+	recorded []HistoryEntry
+}
+
+func (t *recorderTarget) RecordMigration(_ context.Context, entry HistoryEntry) error {
+	t.recorded = append(t.recorded, entry)
+	return nil
+}
+
+type checksummedMigration struct {
+	*MockMigration
+}
+
+func (m *checksummedMigration) Checksum() string {
+	return "checksum " + m.ID()
+}
+
+func TestRunner_Execute_RecordMigration(t *testing.T) {
+	t.Run("should record the details of the applied migrations", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ctx := context.Background()
+
+		target := &recorderTarget{MockTarget: NewMockTarget(ctrl)}
+		runner := NewRunner(NewMockSource(ctrl), target)
+
+		m1 := &checksummedMigration{newMockMigration(ctrl, "1")}
+		m1.EXPECT().Description().Return("description 1")
+		m1.EXPECT().Do(ctx).Return(nil)
+
+		target.EXPECT().Add(ctx, m1.ID()).Return(nil)
+
+		_, err := runner.Execute(ctx, &ExecuteRequest{
+			Plan: Plan{
+				{Action: ActionTypeDo, Migration: m1},
+			},
+		})
+		require.NoError(t, err)
+
+		require.Len(t, target.recorded, 1)
+		assert.Equal(t, "1", target.recorded[0].ID)
+		assert.Equal(t, "description 1", target.recorded[0].Description)
+		assert.Equal(t, "checksum 1", target.recorded[0].Checksum)
+		assert.False(t, target.recorded[0].AppliedAt.IsZero())
+	})
+}
@@ -2,6 +2,7 @@ package migrations
 
 import (
 	"context"
+	"fmt"
 )
 
 type memorySource struct {
@@ -37,3 +38,80 @@ func (m *memorySource) Load(_ context.Context) (Repository, error) {
 		byID: m.m,
 	}, nil
 }
+
+type multiSource struct {
+	sources []Source
+	// added are the migrations added directly to the multiSource.
+	added Repository
+}
+
+// MultiSource creates a source that merges the migrations of all the given sources (eg. SQL files and
+// `github.com/jamillosantos/migrations/v2/fnc` migrations) into a single Repository, sorted by ID.
+//
+// The same ID loaded by more than one source fails the load with ErrMigrationAlreadyExists, naming both migrations and
+// the position of their sources. Migrations added directly to it are merged as well.
+func MultiSource(sources ...Source) Source {
+	return &multiSource{
+		sources: sources,
+	}
+}
+
+func (m *multiSource) Add(_ context.Context, migration Migration) error {
+	return m.added.Add(migration)
+}
+
+func (m *multiSource) Load(ctx context.Context) (Repository, error) {
+	var (
+		repo    Repository
+		origins = make(map[string]int)
+	)
+	merge := func(origin int, list []Migration) error {
+		for _, migration := range list {
+			if existing, err := repo.ByID(migration.ID()); err == nil {
+				return WrapMigrationID(fmt.Errorf("%w: %s (%s) and %s (%s)", ErrMigrationAlreadyExists,
+					existing.String(), sourceOrigin(origins[migration.ID()]), migration.String(), sourceOrigin(origin)), migration.ID())
+			}
+			err := repo.Add(migration)
+			if err != nil {
+				return err
+			}
+			origins[migration.ID()] = origin
+		}
+		return nil
+	}
+
+	for i, source := range m.sources {
+		sourceRepo, err := source.Load(ctx)
+		if err != nil {
+			return Repository{}, fmt.Errorf("failed loading source %d: %w", i+1, err)
+		}
+		list, err := sourceRepo.List(ctx)
+		if err != nil {
+			return Repository{}, err
+		}
+		err = merge(i, list)
+		if err != nil {
+			return Repository{}, err
+		}
+	}
+
+	added, err := m.added.List(ctx)
+	if err != nil {
+		return Repository{}, err
+	}
+	err = merge(-1, added)
+	if err != nil {
+		return Repository{}, err
+	}
+
+	_, err = repo.List(ctx) // Sorts the merged migrations.
+	return repo, err
+}
+
+// sourceOrigin describes the source, by its position in the MultiSource, where a migration was loaded from.
+func sourceOrigin(i int) string {
+	if i < 0 {
+		return "added"
+	}
+	return fmt.Sprintf("source %d", i+1)
+}
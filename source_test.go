@@ -0,0 +1,67 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newMemorySourceWith(t *testing.T, migrations ...Migration) Source {
+	s := NewMemorySource()
+	for _, m := range migrations {
+		require.NoError(t, s.Add(context.Background(), m))
+	}
+	return s
+}
+
+func Test_MultiSource(t *testing.T) {
+	t.Run("should merge the migrations of all sources sorted by ID", func(t *testing.T) {
+		ctx := context.Background()
+		m1 := NewMigration("1", "create customers", nil, nil)
+		m2 := NewMigration("2", "insert customers", nil, nil)
+		m3 := NewMigration("3", "create orders", nil, nil)
+		m4 := NewMigration("4", "insert orders", nil, nil)
+
+		s := MultiSource(newMemorySourceWith(t, m3, m1), newMemorySourceWith(t, m2))
+		require.NoError(t, s.Add(ctx, m4))
+
+		repo, err := s.Load(ctx)
+		require.NoError(t, err)
+		list, err := repo.List(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []Migration{m1, m2, m3, m4}, list)
+
+		got, err := repo.ByID("2")
+		require.NoError(t, err)
+		assert.Equal(t, m2, got)
+	})
+
+	t.Run("should fail when the same ID is loaded by two sources", func(t *testing.T) {
+		s := MultiSource(
+			newMemorySourceWith(t, NewMigration("1", "create customers", nil, nil)),
+			newMemorySourceWith(t, NewMigration("1", "create orders", nil, nil)),
+		)
+
+		_, err := s.Load(context.Background())
+		assert.ErrorIs(t, err, ErrMigrationAlreadyExists)
+		assert.ErrorContains(t, err, "1_create customers (source 1) and 1_create orders (source 2)")
+		var idErr MigrationIDError
+		require.ErrorAs(t, err, &idErr)
+		assert.Equal(t, "1", idErr.MigrationID())
+	})
+
+	t.Run("should fail when a source fails loading", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		wantErr := errors.New("random error")
+		failingSource := NewMockSource(ctrl)
+		failingSource.EXPECT().Load(gomock.Any()).Return(Repository{}, wantErr)
+
+		_, err := MultiSource(newMemorySourceWith(t), failingSource).Load(context.Background())
+		assert.ErrorIs(t, err, wantErr)
+		assert.ErrorContains(t, err, "source 2")
+	})
+}
@@ -0,0 +1,102 @@
+package sql
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jamillosantos/migrations/v2"
+)
+
+const (
+	directivePrefix = "-- migrations:"
+
+	directiveNoTransaction = "no-transaction"
+	directiveTimeout       = "timeout"
+	directiveTags          = "tags"
+	directiveRequires      = "requires"
+)
+
+var ErrInvalidDirective = errors.New("invalid directive")
+
+// parseDirectives returns the directives declared in the header of a migration file. The header is formed by the
+// comment lines at the beginning of the file and each directive is declared in its own line, as in:
+//
+//	-- migrations: no-transaction
+//	-- migrations: timeout=30s
+//	-- migrations: tags=data,slow
+//	-- migrations: requires=20250109011242
+func parseDirectives(content string) []string {
+	directives := make([]string, 0)
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "--") {
+			// The header is over.
+			break
+		}
+		if directive, ok := strings.CutPrefix(line, directivePrefix); ok {
+			directives = append(directives, strings.TrimSpace(directive))
+		}
+	}
+	return directives
+}
+
+// fileDirectives are the directives declared in the header of a migration file.
+type fileDirectives struct {
+	noTransaction bool
+	metadata      migrations.MigrationMetadata
+}
+
+// parseFileDirectives parses the directives of the header of a migration file. Unknown directives, or directives with
+// invalid values, fail with ErrInvalidDirective.
+func parseFileDirectives(content string) (fileDirectives, error) {
+	var result fileDirectives
+	for _, directive := range parseDirectives(content) {
+		name, value, _ := strings.Cut(directive, "=")
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+
+		switch name {
+		case directiveNoTransaction:
+			result.noTransaction = true
+		case directiveTimeout:
+			timeout, err := time.ParseDuration(value)
+			if err != nil || timeout <= 0 {
+				return fileDirectives{}, fmt.Errorf("%w: %q is not a valid timeout", ErrInvalidDirective, directive)
+			}
+			result.metadata.Timeout = timeout
+		case directiveTags:
+			result.metadata.Tags = append(result.metadata.Tags, splitDirectiveList(value)...)
+		case directiveRequires:
+			result.metadata.Requires = append(result.metadata.Requires, splitDirectiveList(value)...)
+		default:
+			return fileDirectives{}, fmt.Errorf("%w: %q is unknown", ErrInvalidDirective, directive)
+		}
+	}
+	return result, nil
+}
+
+// merge combines the directives of the do and undo files of a migration. The timeout of the do file prevails.
+func (d fileDirectives) merge(other fileDirectives) fileDirectives {
+	d.noTransaction = d.noTransaction || other.noTransaction
+	if d.metadata.Timeout == 0 {
+		d.metadata.Timeout = other.metadata.Timeout
+	}
+	d.metadata.Tags = append(d.metadata.Tags, other.metadata.Tags...)
+	d.metadata.Requires = append(d.metadata.Requires, other.metadata.Requires...)
+	return d
+}
+
+func splitDirectiveList(value string) []string {
+	result := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
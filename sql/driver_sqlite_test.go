@@ -0,0 +1,174 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jamillosantos/migrations/v2/sql/drivers"
+)
+
+var _ = Describe("SQLite", func() {
+	var (
+		db1, db2         *sql.DB
+		target1, target2 *Target
+
+		ctx context.Context
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		// Two connections pools to the same file simulate two processes sharing the database.
+		dsn := filepath.Join(GinkgoT().TempDir(), "database.db")
+
+		newDB, err := sql.Open("sqlite3", dsn)
+		Expect(err).ToNot(HaveOccurred(), "should open the database")
+		db1 = newDB
+
+		newDB, err = sql.Open("sqlite3", dsn)
+		Expect(err).ToNot(HaveOccurred(), "should open the database")
+		db2 = newDB
+
+		newTarget, err := NewTarget(db1)
+		Expect(err).ToNot(HaveOccurred(), "should create the target")
+		target1 = newTarget
+
+		newTarget, err = NewTarget(db2)
+		Expect(err).ToNot(HaveOccurred(), "should create the target")
+		target2 = newTarget
+	})
+
+	AfterEach(func() {
+		Expect(db1.Close()).To(Succeed())
+		Expect(db2.Close()).To(Succeed())
+	})
+
+	It("should block another instance while locked", func() {
+		unlocker, err := target1.Lock(ctx)
+		Expect(err).ToNot(HaveOccurred())
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, 300*time.Millisecond)
+		defer cancel()
+		_, err = target2.Lock(timeoutCtx)
+		Expect(err).To(MatchError(drivers.ErrLockNotAcquired))
+
+		Expect(unlocker.Unlock(ctx)).To(Succeed())
+
+		unlocker, err = target2.Lock(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(unlocker.Unlock(ctx)).To(Succeed())
+	})
+
+	It("should acquire the lock as soon as it is released", func() {
+		unlocker, err := target1.Lock(ctx)
+		Expect(err).ToNot(HaveOccurred())
+
+		go func() {
+			defer GinkgoRecover()
+			time.Sleep(200 * time.Millisecond)
+			Expect(unlocker.Unlock(ctx)).To(Succeed())
+		}()
+
+		unlocker2, err := target2.Lock(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(unlocker2.Unlock(ctx)).To(Succeed())
+	})
+
+	It("should take over a lock left behind by a process that died", func() {
+		unlocker, err := target1.Lock(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(unlocker.Unlock(ctx)).To(Succeed())
+
+		// The lock of a dead process is not refreshed by its heartbeat anymore.
+		_, err = db1.ExecContext(ctx, "INSERT INTO _migrations_lock (id, owner, acquired_at) VALUES (1, 'dead', ?)", time.Now().Add(-time.Hour).UnixMilli())
+		Expect(err).ToNot(HaveOccurred())
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, time.Second)
+		defer cancel()
+		unlocker, err = target2.Lock(timeoutCtx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(unlocker.Unlock(ctx)).To(Succeed())
+	})
+
+	It("should not take over a lock that is being refreshed", func() {
+		unlocker, err := target1.Lock(ctx)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = db1.ExecContext(ctx, "UPDATE _migrations_lock SET acquired_at = ?", time.Now().Add(-time.Second).UnixMilli())
+		Expect(err).ToNot(HaveOccurred())
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, 300*time.Millisecond)
+		defer cancel()
+		_, err = target2.Lock(timeoutCtx)
+		Expect(err).To(MatchError(drivers.ErrLockNotAcquired))
+
+		Expect(unlocker.Unlock(ctx)).To(Succeed())
+	})
+
+	It("should not take over a lock before the configured expiration", func() {
+		newTarget, err := NewTarget(db2, WithDriverOptions(drivers.WithLockExpiration(2*time.Hour)))
+		Expect(err).ToNot(HaveOccurred())
+
+		unlocker, err := target1.Lock(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(unlocker.Unlock(ctx)).To(Succeed())
+		_, err = db1.ExecContext(ctx, "INSERT INTO _migrations_lock (id, owner, acquired_at) VALUES (1, 'slow', ?)", time.Now().Add(-time.Hour).UnixMilli())
+		Expect(err).ToNot(HaveOccurred())
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, 300*time.Millisecond)
+		defer cancel()
+		_, err = newTarget.Lock(timeoutCtx)
+		Expect(err).To(MatchError(drivers.ErrLockNotAcquired))
+	})
+
+	It("should refresh the lock when recording a migration", func() {
+		Expect(target1.Create(ctx)).To(Succeed())
+		unlocker, err := target1.Lock(ctx)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = db1.ExecContext(ctx, "UPDATE _migrations_lock SET acquired_at = ?", time.Now().Add(-time.Hour).UnixMilli())
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(target1.Transaction(ctx, func(ctx context.Context) error {
+			return target1.Add(ctx, "1")
+		})).To(Succeed())
+
+		var acquiredAt int64
+		Expect(db1.QueryRowContext(ctx, "SELECT acquired_at FROM _migrations_lock").Scan(&acquiredAt)).To(Succeed())
+		Expect(time.UnixMilli(acquiredAt)).To(BeTemporally("~", time.Now(), time.Minute))
+
+		Expect(unlocker.Unlock(ctx)).To(Succeed())
+	})
+
+	It("should fail recording migrations after the lock was taken over", func() {
+		Expect(target1.Create(ctx)).To(Succeed())
+		unlocker, err := target1.Lock(ctx)
+		Expect(err).ToNot(HaveOccurred())
+
+		// Another instance took the lock over, as when the lock was not refreshed within its expiration.
+		_, err = db2.ExecContext(ctx, "UPDATE _migrations_lock SET owner = 'other'")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(target1.Add(ctx, "1")).To(MatchError(drivers.ErrLockLost))
+		Expect(target1.Done(ctx)).To(BeEmpty())
+		Expect(unlocker.Unlock(ctx)).To(MatchError(drivers.ErrLockLost))
+
+		var owner string
+		Expect(db1.QueryRowContext(ctx, "SELECT owner FROM _migrations_lock").Scan(&owner)).To(Succeed())
+		Expect(owner).To(Equal("other"))
+	})
+
+	It("should record migrations without a database name", func() {
+		Expect(target1.Create(ctx)).To(Succeed())
+		Expect(target1.Add(ctx, "1")).To(Succeed())
+		Expect(target1.FinishMigration(ctx, "1")).To(Succeed())
+
+		Expect(target2.Done(ctx)).To(Equal([]string{"1"}))
+	})
+})
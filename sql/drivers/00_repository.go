@@ -1,6 +1,7 @@
 package drivers
 
 import (
+	"context"
 	"database/sql/driver"
 	"errors"
 	"reflect"
@@ -14,6 +15,21 @@ var drivers = map[string]DriverConstructor{
 	"sql":        newSQL,
 	"postgres":   newPostgres,
 	"*pq.Driver": newPostgres,
+
+	"mysql":              newMySQL,
+	"*mysql.MySQLDriver": newMySQL,
+
+	"sqlite3":               newSQLite,
+	"*sqlite3.SQLiteDriver": newSQLite,
+
+	"sqlserver":     newSQLServer,
+	"mssql":         newSQLServer,
+	"*mssql.Driver": newSQLServer,
+}
+
+// busyDialects are the dialects that detect the errors of a busy database, by the type of the database/sql driver.
+var busyDialects = map[string]dialect{
+	"*sqlite3.SQLiteDriver": sqliteDialect,
 }
 
 // Register will register a new driver constructor for the given driver name.
@@ -34,3 +50,15 @@ func DriverFromDB(db DBWithDriver, options ...Option) (Driver, error) {
 	}
 	return constructor(db, options...)
 }
+
+// RetryBusy runs fn, running it again with an exponential backoff while it fails because the database is busy, as the
+// drivers do with their own queries. Only the dialects that detect busy errors, by the driver of the db, retry. For any
+// other database, or a nil db, fn runs once.
+//
+// fn must be safe to run again after failing, as a single statement that the database did not execute.
+func RetryBusy(ctx context.Context, db interface{ Driver() driver.Driver }, fn func() error) error {
+	if db == nil {
+		return fn()
+	}
+	return busyDialects[reflect.TypeOf(db.Driver()).String()].retry(ctx, fn)
+}
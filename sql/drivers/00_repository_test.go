@@ -0,0 +1,38 @@
+package drivers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type customDriver struct {
+	Driver
+}
+
+func TestDriverFromDB(t *testing.T) {
+	t.Run("should use the driver registered for the database driver type", func(t *testing.T) {
+		db, _ := newFakeDB(t)
+
+		want := &customDriver{}
+		Register("drivers.fakeDriver", func(_ DB, _ ...Option) (Driver, error) {
+			return want, nil
+		})
+		t.Cleanup(func() {
+			delete(drivers, "drivers.fakeDriver")
+		})
+
+		got, err := DriverFromDB(db)
+		require.NoError(t, err)
+		assert.Same(t, want, got)
+	})
+
+	t.Run("should fall back to the generic driver", func(t *testing.T) {
+		db, _ := newFakeDB(t)
+
+		got, err := DriverFromDB(db, WithDatabaseName("test"))
+		require.NoError(t, err)
+		assert.IsType(t, &sqlDriver{}, got)
+	})
+}
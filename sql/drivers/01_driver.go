@@ -7,6 +7,11 @@ import (
 	"github.com/jamillosantos/migrations/v2"
 )
 
+// Driver handles the bookkeeping of the migrations applied and the locking of a database. Drivers for other databases
+// can be registered with Register.
+//
+// Drivers can also own the DDL of the migrations table (SchemaDriver) and the queries that read it (HistoryDriver). The
+// sql.Target uses the generic SQL of NewGenericDriver for the optional interfaces a driver does not implement.
 type Driver interface {
 	Add(ctx context.Context, id string) error
 	Remove(ctx context.Context, id string) error
@@ -15,6 +20,39 @@ type Driver interface {
 	Lock(ctx context.Context) (migrations.Unlocker, error)
 }
 
+// SchemaDriver is an optional interface for drivers that own the DDL of the migrations table.
+type SchemaDriver interface {
+	// Create creates the migrations table, if it does not exist, upgrading tables created by older versions.
+	Create(ctx context.Context) error
+	// Destroy drops the migrations table.
+	Destroy(ctx context.Context) error
+	// Exists reports whether the migrations table exists.
+	Exists(ctx context.Context) (bool, error)
+}
+
+// HistoryDriver is an optional interface for drivers that own the queries that list the migrations applied and record
+// the details of their execution.
+type HistoryDriver interface {
+	// Current returns the ID of the last migration applied, or migrations.ErrNoCurrentMigration if there is none.
+	Current(ctx context.Context) (string, error)
+	// Done lists the IDs of the applied migrations, ordered by ID. It fails with migrations.ErrDirtyMigration if any of
+	// them is dirty.
+	Done(ctx context.Context) ([]string, error)
+	// History lists all migrations recorded, including the dirty ones, ordered by ID.
+	History(ctx context.Context) ([]migrations.HistoryEntry, error)
+	// RecordMigration marks the migration as finished, storing the details of its execution.
+	RecordMigration(ctx context.Context, entry migrations.HistoryEntry) error
+	// UpdateChecksum replaces the checksum recorded for the migration.
+	UpdateChecksum(ctx context.Context, id string, checksum string) error
+}
+
+// TableDriver is a Driver that implements all the optional interfaces, as the drivers of this package do.
+type TableDriver interface {
+	Driver
+	SchemaDriver
+	HistoryDriver
+}
+
 type DriverConstructor func(db DB, options ...Option) (Driver, error)
 
 type DB interface {
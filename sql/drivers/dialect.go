@@ -0,0 +1,86 @@
+package drivers
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+const (
+	busyRetryAttempts = 10
+	busyRetryDelay    = 10 * time.Millisecond
+	busyRetryMaxDelay = time.Second
+)
+
+var placeholderRegexp = regexp.MustCompile(`\$(\d+)`)
+
+// column is the definition of a column of the migrations table.
+type column struct {
+	name       string
+	definition string
+}
+
+// dialect holds what differs between the SQL databases supported by the drivers based on the sqlDriver.
+type dialect struct {
+	// placeholder formats the positional parameter n (starting from 1) of a query.
+	placeholder func(n int) string
+	// quote quotes an identifier, if needed. Identifiers are validated, so they never have quotes.
+	quote func(identifier string) string
+	// columns are the columns of the migrations table. Columns missing in tables created by older versions are added
+	// when the table is created.
+	columns []column
+	// isBusy reports whether the error is caused by the database being busy, in which case the query is retried.
+	// When nil, queries are never retried.
+	isBusy func(err error) bool
+}
+
+// sqlDialect is the dialect of the generic sqlDriver, that is also compatible with Postgres and SQLite.
+var sqlDialect = dialect{
+	placeholder: func(n int) string {
+		return "$" + strconv.Itoa(n)
+	},
+	quote: quoteDouble,
+	columns: []column{
+		{"id", "text PRIMARY KEY"},
+		{"dirty", "bool default true"},
+		{"applied_at", "timestamp"},
+		{"execution_time_ms", "bigint"},
+		{"checksum", "text"},
+		{"description", "text"},
+	},
+}
+
+// rebind replaces the `$n` placeholders of the query by the placeholders of the dialect.
+func (d dialect) rebind(query string) string {
+	return placeholderRegexp.ReplaceAllStringFunc(query, func(placeholder string) string {
+		n, _ := strconv.Atoi(placeholder[1:])
+		return d.placeholder(n)
+	})
+}
+
+// table returns the quoted, and schema-qualified when there is a schema, name of the table.
+func (d dialect) table(t tableName) string {
+	if t.schema == "" {
+		return d.quote(t.name)
+	}
+	return d.quote(t.schema) + "." + d.quote(t.name)
+}
+
+// retry runs fn, running it again, with an exponential backoff, while it fails because the database is busy.
+func (d dialect) retry(ctx context.Context, fn func() error) error {
+	delay := busyRetryDelay
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || d.isBusy == nil || !d.isBusy(err) || attempt == busyRetryAttempts {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, busyRetryMaxDelay)
+	}
+}
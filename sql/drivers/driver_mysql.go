@@ -0,0 +1,133 @@
+package drivers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jamillosantos/migrations/v2"
+)
+
+var ErrLockNotAcquired = errors.New("failed acquiring the migrations lock")
+
+// mysqlDialect uses the `?` placeholders and column types supported by MySQL and MariaDB. MySQL does not allow `text`
+// primary keys, so the ID is stored as a VARCHAR.
+//
+// The `applied_at` column is a DATETIME, which is scanned as a time.Time with `parseTime=true` in the DSN, or parsed
+// from its text otherwise.
+var mysqlDialect = dialect{
+	placeholder: func(_ int) string {
+		return "?"
+	},
+	quote: func(identifier string) string {
+		return quoteIdentifier(identifier, "`", "`")
+	},
+	columns: []column{
+		{"id", "VARCHAR(255) PRIMARY KEY"},
+		{"dirty", "BOOLEAN DEFAULT TRUE"},
+		{"applied_at", "DATETIME(6) NULL"},
+		{"execution_time_ms", "BIGINT"},
+		{"checksum", "VARCHAR(64)"},
+		{"description", "TEXT"},
+	},
+}
+
+type mysqlDriver struct {
+	sqlDriver
+}
+
+func newMySQL(db DB, options ...Option) (Driver, error) {
+	opts := driverOpts{
+		TableName: DefaultMigrationsTableName,
+	}
+
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	if opts.Ctx == nil {
+		opts.Ctx = context.Background()
+	}
+
+	if opts.DatabaseName == "" {
+		rows, err := db.QueryContext(opts.Ctx, "SELECT DATABASE()")
+		if err != nil {
+			return nil, fmt.Errorf("error obtaining current database name: %w", err)
+		}
+		defer func() {
+			_ = rows.Close()
+		}()
+
+		var databaseName sql.NullString
+		if rows.Next() {
+			err = rows.Scan(&databaseName)
+			if err != nil {
+				return nil, fmt.Errorf("error scanning current database name: %w", err)
+			}
+		}
+		if !databaseName.Valid || databaseName.String == "" {
+			return nil, ErrMissingDatabaseName
+		}
+		opts.DatabaseName = databaseName.String
+	}
+
+	d, err := newSQLDriver(db, mysqlDialect, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &mysqlDriver{d}, nil
+}
+
+// Exists reports whether the migrations table exists. A table without a schema is looked up in the current database.
+func (p *mysqlDriver) Exists(ctx context.Context) (bool, error) {
+	if p.table.schema == "" {
+		return p.exists(ctx, "SELECT count(*) FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = $1", p.table.name)
+	}
+	return p.exists(ctx, "SELECT count(*) FROM information_schema.tables WHERE table_schema = $1 AND table_name = $2", p.table.schema, p.table.name)
+}
+
+// mysqlLocker is the migrations.Unlocker implementation for MySQL. MySQL named locks belong to the session that
+// acquired them, so the lock is held by a transaction that pins its connection until Unlock is called.
+type mysqlLocker struct {
+	db   *sql.Tx
+	name string
+}
+
+func (p *mysqlLocker) Unlock(ctx context.Context) error {
+	_, err := p.db.ExecContext(ctx, "SELECT RELEASE_LOCK(?)", p.name)
+	if err != nil {
+		_ = p.db.Rollback()
+		return fmt.Errorf("failed unlocking migration: %w", err)
+	}
+	_ = p.db.Commit()
+	return nil
+}
+
+// Lock acquires a named lock, using GET_LOCK, whose name is derived from the database and table names. It waits until
+// the lock is released by any other instance of the migration system.
+func (p *mysqlDriver) Lock(ctx context.Context) (migrations.Unlocker, error) {
+	lockID, err := p.generateLockID()
+	if err != nil {
+		return nil, fmt.Errorf("failed locking database: %w", err)
+	}
+	// MySQL limits the lock names to 64 characters, so the hash is used instead of the database and table names.
+	name := fmt.Sprintf("migrations:%d", lockID)
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed starting transaction for locking: %w", err)
+	}
+
+	var acquired sql.NullInt64
+	err = tx.QueryRowContext(ctx, "SELECT GET_LOCK(?, -1)", name).Scan(&acquired)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, fmt.Errorf("failed locking database: %w", err)
+	}
+	if acquired.Int64 != 1 {
+		_ = tx.Rollback()
+		return nil, ErrLockNotAcquired
+	}
+	return &mysqlLocker{db: tx, name: name}, nil
+}
@@ -0,0 +1,256 @@
+package drivers
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jamillosantos/migrations/v2"
+)
+
+func TestMySQL_newMySQL(t *testing.T) {
+	t.Run("should obtain the database name", func(t *testing.T) {
+		db, conn := newFakeDB(t, fakeResponse{
+			match:   "SELECT DATABASE()",
+			columns: []string{""},
+			rows:    [][]driver.Value{{"shop"}},
+		})
+
+		d, err := newMySQL(db)
+		require.NoError(t, err)
+		assert.Equal(t, "shop", d.(*mysqlDriver).databaseName)
+		assert.Equal(t, []string{"SELECT DATABASE()"}, conn.Queries())
+	})
+
+	t.Run("should fail when there is no current database", func(t *testing.T) {
+		db, _ := newFakeDB(t, fakeResponse{
+			match:   "SELECT DATABASE()",
+			columns: []string{""},
+			rows:    [][]driver.Value{{nil}},
+		})
+
+		_, err := newMySQL(db)
+		assert.ErrorIs(t, err, ErrMissingDatabaseName)
+	})
+
+	t.Run("should fail when the database name cannot be obtained", func(t *testing.T) {
+		wantErr := errors.New("random error")
+		db, _ := newFakeDB(t, fakeResponse{
+			match: "SELECT DATABASE()",
+			err:   wantErr,
+		})
+
+		_, err := newMySQL(db)
+		assert.ErrorIs(t, err, wantErr)
+	})
+}
+
+func TestMySQL_Create(t *testing.T) {
+	t.Run("should create the table with MySQL types", func(t *testing.T) {
+		ctx := context.Background()
+		db, conn := newFakeDB(t, fakeResponse{
+			match:   "WHERE 1 = 0",
+			columns: []string{"id", "dirty", "applied_at", "execution_time_ms", "checksum", "description"},
+		})
+
+		d, err := newMySQL(db, WithDatabaseName("shop"))
+		require.NoError(t, err)
+
+		require.NoError(t, d.(TableDriver).Create(ctx))
+		assert.Equal(t, []string{
+			"CREATE TABLE IF NOT EXISTS _migrations (id VARCHAR(255) PRIMARY KEY, dirty BOOLEAN DEFAULT TRUE, applied_at DATETIME(6) NULL, execution_time_ms BIGINT, checksum VARCHAR(64), description TEXT)",
+			"SELECT * FROM _migrations WHERE 1 = 0",
+		}, conn.Queries())
+	})
+
+	t.Run("should add the columns missing in older tables", func(t *testing.T) {
+		ctx := context.Background()
+		db, conn := newFakeDB(t, fakeResponse{
+			match:   "WHERE 1 = 0",
+			columns: []string{"id", "dirty"},
+		})
+
+		d, err := newMySQL(db, WithDatabaseName("shop"), WithTableName("ops.my-migrations"))
+		require.NoError(t, err)
+
+		require.NoError(t, d.(TableDriver).Create(ctx))
+		assert.Equal(t, []string{
+			"ALTER TABLE ops.`my-migrations` ADD applied_at DATETIME(6) NULL",
+			"ALTER TABLE ops.`my-migrations` ADD execution_time_ms BIGINT",
+			"ALTER TABLE ops.`my-migrations` ADD checksum VARCHAR(64)",
+			"ALTER TABLE ops.`my-migrations` ADD description TEXT",
+		}, conn.Queries()[2:])
+	})
+}
+
+func TestMySQL_Queries(t *testing.T) {
+	t.Run("should use the ? placeholders", func(t *testing.T) {
+		ctx := context.Background()
+		db, conn := newFakeDB(t)
+
+		d, err := newMySQL(db, WithDatabaseName("shop"))
+		require.NoError(t, err)
+
+		appliedAt := time.Date(2025, 1, 9, 1, 12, 42, 0, time.UTC)
+		require.NoError(t, d.Add(ctx, "1"))
+		require.NoError(t, d.(TableDriver).RecordMigration(ctx, migrations.HistoryEntry{
+			ID:            "1",
+			Description:   "create table",
+			Checksum:      "checksum",
+			AppliedAt:     appliedAt,
+			ExecutionTime: 2 * time.Second,
+		}))
+
+		require.Len(t, conn.queries, 2)
+		assert.Equal(t, fakeQuery{
+			query: "INSERT INTO _migrations (id, dirty) VALUES (?, ?)",
+			args:  []driver.Value{"1", true},
+		}, conn.queries[0])
+		assert.Equal(t, fakeQuery{
+			query: "UPDATE _migrations SET dirty = ?, applied_at = ?, execution_time_ms = ?, checksum = ?, description = ? WHERE id = ?",
+			args:  []driver.Value{false, appliedAt, int64(2000), "checksum", "create table", "1"},
+		}, conn.queries[1])
+	})
+
+	t.Run("should check the table exists in the current database", func(t *testing.T) {
+		ctx := context.Background()
+		db, conn := newFakeDB(t, fakeResponse{
+			match:   "information_schema.tables",
+			columns: []string{""},
+			rows:    [][]driver.Value{{int64(1)}},
+		})
+
+		d, err := newMySQL(db, WithDatabaseName("shop"))
+		require.NoError(t, err)
+
+		exists, err := d.(TableDriver).Exists(ctx)
+		require.NoError(t, err)
+		assert.True(t, exists)
+		assert.Equal(t, fakeQuery{
+			query: "SELECT count(*) FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?",
+			args:  []driver.Value{"_migrations"},
+		}, conn.queries[0])
+	})
+
+	t.Run("should read the history without parseTime in the DSN", func(t *testing.T) {
+		ctx := context.Background()
+		// Without parseTime, the MySQL driver returns every column as text.
+		db, _ := newFakeDB(t, fakeResponse{
+			match:   "WHERE 1 = 0",
+			columns: []string{"id", "dirty", "applied_at", "execution_time_ms", "checksum", "description"},
+		}, fakeResponse{
+			match:   "ORDER BY id",
+			columns: []string{"id", "dirty", "applied_at", "execution_time_ms", "checksum", "description"},
+			rows: [][]driver.Value{
+				{[]byte("1"), []byte("0"), []byte("2025-01-09 01:12:42.500000"), []byte("2000"), []byte("checksum"), []byte("create table")},
+				{[]byte("2"), []byte("1"), nil, nil, nil, nil},
+			},
+		})
+
+		d, err := newMySQL(db, WithDatabaseName("shop"))
+		require.NoError(t, err)
+
+		history, err := d.(TableDriver).History(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []migrations.HistoryEntry{
+			{
+				ID:            "1",
+				Description:   "create table",
+				Checksum:      "checksum",
+				AppliedAt:     time.Date(2025, 1, 9, 1, 12, 42, 500000000, time.UTC),
+				ExecutionTime: 2 * time.Second,
+			},
+			{ID: "2", Dirty: true},
+		}, history)
+
+		_, err = d.(TableDriver).Done(ctx)
+		assert.ErrorIs(t, err, migrations.ErrDirtyMigration)
+	})
+}
+
+func TestMySQL_Lock(t *testing.T) {
+	t.Run("should lock and unlock using a named lock", func(t *testing.T) {
+		ctx := context.Background()
+		db, conn := newFakeDB(t, fakeResponse{
+			match:   "GET_LOCK",
+			columns: []string{""},
+			rows:    [][]driver.Value{{int64(1)}},
+		})
+
+		d, err := newMySQL(db, WithDatabaseName("shop"))
+		require.NoError(t, err)
+
+		unlocker, err := d.Lock(ctx)
+		require.NoError(t, err)
+		require.NoError(t, unlocker.Unlock(ctx))
+
+		lockID, err := d.(*mysqlDriver).generateLockID()
+		require.NoError(t, err)
+		name := fmt.Sprintf("migrations:%d", lockID)
+
+		assert.Equal(t, []fakeQuery{
+			{query: "SELECT GET_LOCK(?, -1)", args: []driver.Value{name}},
+			{query: "SELECT RELEASE_LOCK(?)", args: []driver.Value{name}},
+		}, conn.queries)
+		assert.Equal(t, 1, conn.commits)
+	})
+
+	t.Run("should fail when the lock is not granted", func(t *testing.T) {
+		ctx := context.Background()
+		db, conn := newFakeDB(t, fakeResponse{
+			match:   "GET_LOCK",
+			columns: []string{""},
+			rows:    [][]driver.Value{{nil}},
+		})
+
+		d, err := newMySQL(db, WithDatabaseName("shop"))
+		require.NoError(t, err)
+
+		_, err = d.Lock(ctx)
+		assert.ErrorIs(t, err, ErrLockNotAcquired)
+		assert.Equal(t, 1, conn.rollbacks)
+	})
+
+	t.Run("should fail when the lock query fails", func(t *testing.T) {
+		ctx := context.Background()
+		wantErr := errors.New("random error")
+		db, conn := newFakeDB(t, fakeResponse{
+			match: "GET_LOCK",
+			err:   wantErr,
+		})
+
+		d, err := newMySQL(db, WithDatabaseName("shop"))
+		require.NoError(t, err)
+
+		_, err = d.Lock(ctx)
+		assert.ErrorIs(t, err, wantErr)
+		assert.Equal(t, 1, conn.rollbacks)
+	})
+
+	t.Run("should fail when the unlock fails", func(t *testing.T) {
+		ctx := context.Background()
+		wantErr := errors.New("random error")
+		db, conn := newFakeDB(t, fakeResponse{
+			match:   "GET_LOCK",
+			columns: []string{""},
+			rows:    [][]driver.Value{{int64(1)}},
+		}, fakeResponse{
+			match: "RELEASE_LOCK",
+			err:   wantErr,
+		})
+
+		d, err := newMySQL(db, WithDatabaseName("shop"))
+		require.NoError(t, err)
+
+		unlocker, err := d.Lock(ctx)
+		require.NoError(t, err)
+		assert.ErrorIs(t, unlocker.Unlock(ctx), wantErr)
+		assert.Equal(t, 1, conn.rollbacks)
+	})
+}
@@ -9,6 +9,7 @@ import (
 
 type pgDriver struct {
 	sqlDriver
+	createSchema bool
 }
 
 func newPostgres(db DB, options ...Option) (Driver, error) {
@@ -43,15 +44,33 @@ func newPostgres(db DB, options ...Option) (Driver, error) {
 		}
 	}
 
+	d, err := newSQLDriver(db, sqlDialect, opts)
+	if err != nil {
+		return nil, err
+	}
 	return &pgDriver{
-		sqlDriver{
-			db:           db,
-			databaseName: opts.DatabaseName,
-			tableName:    opts.TableName,
-		},
+		sqlDriver:    d,
+		createSchema: opts.CreateSchema,
 	}, nil
 }
 
+// Create creates the migrations table and, when WithCreateSchema is used, its schema.
+func (p *pgDriver) Create(ctx context.Context) error {
+	if p.createSchema && p.table.schema != "" {
+		_, err := execerFromContext(ctx, p.db).ExecContext(ctx, "CREATE SCHEMA IF NOT EXISTS "+p.dialect.quote(p.table.schema))
+		if err != nil {
+			return fmt.Errorf("failed creating the schema of the migrations table: %w", err)
+		}
+	}
+	return p.sqlDriver.Create(ctx)
+}
+
+// Exists reports whether the migrations table exists. The name is resolved by to_regclass as the queries resolve it,
+// folding unquoted identifiers and looking up a table without a schema in the search path.
+func (p *pgDriver) Exists(ctx context.Context) (bool, error) {
+	return p.exists(ctx, "SELECT count(*) WHERE to_regclass($1) IS NOT NULL", p.dialect.table(p.table))
+}
+
 // pgLocker is the migrations.Locker implementation for sqlDriver database. Its job is to block other instances of the
 // migration system to run at the same time. In other to achieve this, it uses the database and table name to create a
 // unique key that is hashed (using murmur3) to a bigint. Then, an advisory lock is created using that key.
@@ -0,0 +1,71 @@
+package drivers
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgres_Create(t *testing.T) {
+	t.Run("should create the schema of the table", func(t *testing.T) {
+		ctx := context.Background()
+		db, conn := newFakeDB(t, fakeResponse{
+			match:   "WHERE 1 = 0",
+			columns: []string{"id", "dirty", "applied_at", "execution_time_ms", "checksum", "description"},
+		})
+
+		d, err := newPostgres(db, WithDatabaseName("test"), WithTableName("my-ops._migrations"), WithCreateSchema())
+		require.NoError(t, err)
+
+		require.NoError(t, d.(TableDriver).Create(ctx))
+		queries := conn.Queries()
+		require.Len(t, queries, 3)
+		assert.Equal(t, `CREATE SCHEMA IF NOT EXISTS "my-ops"`, queries[0])
+		assert.Contains(t, queries[1], `CREATE TABLE IF NOT EXISTS "my-ops"._migrations (`)
+	})
+
+	t.Run("should not create the schema by default", func(t *testing.T) {
+		ctx := context.Background()
+		db, conn := newFakeDB(t, fakeResponse{
+			match:   "WHERE 1 = 0",
+			columns: []string{"id", "dirty", "applied_at", "execution_time_ms", "checksum", "description"},
+		})
+
+		d, err := newPostgres(db, WithDatabaseName("test"), WithTableName("ops._migrations"))
+		require.NoError(t, err)
+
+		require.NoError(t, d.(TableDriver).Create(ctx))
+		assert.NotContains(t, conn.Queries()[0], "CREATE SCHEMA")
+	})
+
+	t.Run("should fail with an invalid table name", func(t *testing.T) {
+		db, _ := newFakeDB(t)
+
+		_, err := newPostgres(db, WithDatabaseName("test"), WithTableName("ops._migrations; --"))
+		assert.ErrorIs(t, err, ErrInvalidTableName)
+	})
+}
+
+func TestPostgres_Exists(t *testing.T) {
+	t.Run("should resolve the table name as the queries do", func(t *testing.T) {
+		ctx := context.Background()
+		db, conn := newFakeDB(t, fakeResponse{
+			match:   "to_regclass",
+			columns: []string{""},
+			rows:    [][]driver.Value{{int64(1)}},
+		})
+
+		d, err := newPostgres(db, WithDatabaseName("test"), WithTableName("Ops.MyMigrations"))
+		require.NoError(t, err)
+
+		exists, err := d.(TableDriver).Exists(ctx)
+		require.NoError(t, err)
+		assert.True(t, exists)
+		assert.Equal(t, []fakeQuery{
+			{query: "SELECT count(*) WHERE to_regclass($1) IS NOT NULL", args: []driver.Value{"Ops.MyMigrations"}},
+		}, conn.queries)
+	})
+}
@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/jamillosantos/migrations/v2"
 
@@ -24,8 +26,25 @@ type TXExecer interface {
 
 type sqlDriver struct {
 	db           DB
+	dialect      dialect
 	databaseName string
 	tableName    string
+	table        tableName
+}
+
+// newSQLDriver creates the sqlDriver, with the given dialect, that the drivers for specific databases embed.
+func newSQLDriver(db DB, d dialect, opts driverOpts) (sqlDriver, error) {
+	table, err := parseTableName(opts.TableName)
+	if err != nil {
+		return sqlDriver{}, err
+	}
+	return sqlDriver{
+		db:           db,
+		dialect:      d,
+		databaseName: opts.DatabaseName,
+		tableName:    opts.TableName,
+		table:        table,
+	}, nil
 }
 
 func newSQL(db DB, options ...Option) (Driver, error) {
@@ -41,12 +60,32 @@ func newSQL(db DB, options ...Option) (Driver, error) {
 		return nil, ErrMissingDatabaseName
 	}
 
-	return &sqlDriver{
-			db:           db,
-			databaseName: opts.DatabaseName,
-			tableName:    opts.TableName,
-		},
-		nil
+	d, err := newSQLDriver(db, sqlDialect, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// NewGenericDriver creates a driver that handles the migrations table with generic SQL. Its Lock does nothing, as
+// locking is database specific. Unlike the "sql" driver, the database name is not required.
+//
+// sql.Target uses it for the optional interfaces (SchemaDriver and HistoryDriver) that a registered driver does not
+// implement.
+func NewGenericDriver(db DB, options ...Option) (TableDriver, error) {
+	opts := driverOpts{
+		TableName: DefaultMigrationsTableName,
+	}
+
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	d, err := newSQLDriver(db, sqlDialect, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
 }
 
 // noopUnlocker is a dummy implementation of the migrations.Unlocker interface that does nothing. This is used for the
@@ -62,8 +101,193 @@ func (p *sqlDriver) Lock(_ context.Context) (migrations.Unlocker, error) {
 	return &noopUnlocker{}, nil
 }
 
+// Create creates the migrations table, if it does not exist, and adds the columns missing in tables created by older
+// versions. Existing data is kept untouched.
+func (p *sqlDriver) Create(ctx context.Context) error {
+	definitions := make([]string, len(p.dialect.columns))
+	for i, column := range p.dialect.columns {
+		definitions[i] = column.name + " " + column.definition
+	}
+	_, err := p.exec(ctx, "CREATE TABLE IF NOT EXISTS %s ("+strings.Join(definitions, ", ")+")")
+	if err != nil {
+		return err
+	}
+	return p.upgrade(ctx)
+}
+
+func (p *sqlDriver) upgrade(ctx context.Context) error {
+	existing, err := p.existingColumns(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, column := range p.dialect.columns {
+		if _, ok := existing[column.name]; ok {
+			continue
+		}
+		_, err := p.exec(ctx, "ALTER TABLE %s ADD "+column.name+" "+column.definition)
+		if err != nil {
+			return fmt.Errorf("failed upgrading the migrations table: %w", err)
+		}
+	}
+	return nil
+}
+
+// existingColumns lists the columns, lower cased, of the migrations table.
+func (p *sqlDriver) existingColumns(ctx context.Context) (map[string]struct{}, error) {
+	var existing map[string]struct{}
+	err := p.query(ctx, fmt.Sprintf("SELECT * FROM %s WHERE 1 = 0", p.dialect.table(p.table)), nil, func(rs *sql.Rows) error {
+		columns, err := rs.Columns()
+		if err != nil {
+			return err
+		}
+		existing = make(map[string]struct{}, len(columns))
+		for _, column := range columns {
+			existing[strings.ToLower(column)] = struct{}{}
+		}
+		return nil
+	})
+	return existing, err
+}
+
+// Destroy drops the migrations table.
+func (p *sqlDriver) Destroy(ctx context.Context) error {
+	_, err := p.exec(ctx, "DROP TABLE IF EXISTS %s")
+	return err
+}
+
+// Exists reports whether the migrations table exists. A table without a schema is looked up in the current schema.
+// Unquoted identifiers are folded to lower case, as the database does when creating the table.
+func (p *sqlDriver) Exists(ctx context.Context) (bool, error) {
+	table := p.table.folded()
+	if table.schema == "" {
+		return p.exists(ctx, "SELECT count(*) FROM information_schema.tables WHERE table_schema = current_schema() AND table_name = $1", table.name)
+	}
+	return p.exists(ctx, "SELECT count(*) FROM information_schema.tables WHERE table_schema = $1 AND table_name = $2", table.schema, table.name)
+}
+
+func (p *sqlDriver) Current(ctx context.Context) (string, error) {
+	list, err := p.Done(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if len(list) == 0 {
+		return "", migrations.ErrNoCurrentMigration
+	}
+
+	return list[len(list)-1], nil
+}
+
+func (p *sqlDriver) Done(ctx context.Context) ([]string, error) {
+	history, err := p.History(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]string, 0, len(history))
+	for _, entry := range history {
+		if entry.Dirty {
+			return nil, migrations.WrapMigrationID(migrations.ErrDirtyMigration, entry.ID)
+		}
+		result = append(result, entry.ID)
+	}
+	return result, nil
+}
+
+// History lists the migrations recorded, including the dirty ones. The details missing in tables created by older
+// versions, and not upgraded by Create yet, are left empty, so the table can be inspected without being changed.
+func (p *sqlDriver) History(ctx context.Context) ([]migrations.HistoryEntry, error) {
+	existing, err := p.existingColumns(ctx)
+	if err != nil {
+		return nil, err
+	}
+	selected := make([]string, len(p.dialect.columns))
+	for i, column := range p.dialect.columns {
+		selected[i] = column.name
+		if _, ok := existing[column.name]; !ok {
+			selected[i] = "NULL"
+		}
+	}
+
+	var result []migrations.HistoryEntry
+	err = p.query(ctx, fmt.Sprintf("SELECT %s FROM %s ORDER BY id ASC", strings.Join(selected, ", "), p.dialect.table(p.table)), nil, func(rs *sql.Rows) error {
+		result = make([]migrations.HistoryEntry, 0)
+		for rs.Next() {
+			var (
+				entry         migrations.HistoryEntry
+				appliedAt     timestamp
+				executionTime sql.NullInt64
+				checksum      sql.NullString
+				description   sql.NullString
+			)
+			err := rs.Scan(&entry.ID, &entry.Dirty, &appliedAt, &executionTime, &checksum, &description)
+			if err != nil {
+				return err
+			}
+			entry.AppliedAt = appliedAt.Time
+			entry.ExecutionTime = time.Duration(executionTime.Int64) * time.Millisecond
+			entry.Checksum = checksum.String
+			entry.Description = description.String
+			result = append(result, entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// timestampLayouts are the layouts of the timestamps returned as text, as by the MySQL driver when the DSN does not
+// have `parseTime=true` and by SQLite, that has no date types.
+var timestampLayouts = []string{
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02T15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+}
+
+// timestamp is a nullable timestamp that can be scanned from a time.Time or from its text. Timestamps without a time
+// zone are in UTC, as recorded by the runner.
+type timestamp struct {
+	time.Time
+}
+
+func (t *timestamp) Scan(value any) error {
+	var text string
+	switch v := value.(type) {
+	case nil:
+		t.Time = time.Time{}
+		return nil
+	case time.Time:
+		t.Time = v
+		return nil
+	case []byte:
+		text = string(v)
+	case string:
+		text = v
+	default:
+		return fmt.Errorf("cannot scan %T into a timestamp", value)
+	}
+
+	// MySQL zero dates cannot be parsed.
+	if text == "" || strings.HasPrefix(text, "0000-00-00") {
+		t.Time = time.Time{}
+		return nil
+	}
+	for _, layout := range timestampLayouts {
+		parsed, err := time.Parse(layout, text)
+		if err == nil {
+			t.Time = parsed
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot parse %q as a timestamp", text)
+}
+
 func (p *sqlDriver) Add(ctx context.Context, id string) error {
-	_, err := p.db.ExecContext(ctx, fmt.Sprintf("INSERT INTO %s (id, dirty) VALUES ($1, true)", p.tableName), id)
+	_, err := p.exec(ctx, "INSERT INTO %s (id, dirty) VALUES ($1, $2)", id, true)
 	if err != nil {
 		return fmt.Errorf("failed adding migration to the executed list: %w", err)
 	}
@@ -71,7 +295,7 @@ func (p *sqlDriver) Add(ctx context.Context, id string) error {
 }
 
 func (p *sqlDriver) Remove(ctx context.Context, id string) error {
-	result, err := p.db.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE id = $1", p.tableName), id)
+	result, err := p.exec(ctx, "DELETE FROM %s WHERE id = $1", id)
 	if err != nil {
 		return fmt.Errorf("failed removing migration from the executed list: %w", err)
 	}
@@ -85,7 +309,7 @@ func (p *sqlDriver) Remove(ctx context.Context, id string) error {
 }
 
 func (p *sqlDriver) StartMigration(ctx context.Context, id string) error {
-	result, err := p.db.ExecContext(ctx, fmt.Sprintf("UPDATE %s SET dirty = true WHERE id = $1", p.tableName), id)
+	result, err := p.exec(ctx, "UPDATE %s SET dirty = $1 WHERE id = $2", true, id)
 	if err != nil {
 		return fmt.Errorf("failed starting migration: %w", err)
 	}
@@ -98,7 +322,7 @@ func (p *sqlDriver) StartMigration(ctx context.Context, id string) error {
 }
 
 func (p *sqlDriver) FinishMigration(ctx context.Context, id string) error {
-	result, err := p.db.ExecContext(ctx, fmt.Sprintf("UPDATE %s SET dirty = false WHERE id = $1", p.tableName), id)
+	result, err := p.exec(ctx, "UPDATE %s SET dirty = $1 WHERE id = $2", false, id)
 	if err != nil {
 		return fmt.Errorf("failed finishing migration: %w", err)
 	}
@@ -110,6 +334,80 @@ func (p *sqlDriver) FinishMigration(ctx context.Context, id string) error {
 	return nil
 }
 
+func (p *sqlDriver) RecordMigration(ctx context.Context, entry migrations.HistoryEntry) error {
+	result, err := p.exec(ctx, "UPDATE %s SET dirty = $1, applied_at = $2, execution_time_ms = $3, checksum = $4, description = $5 WHERE id = $6",
+		false, entry.AppliedAt, entry.ExecutionTime.Milliseconds(), entry.Checksum, entry.Description, entry.ID)
+	if err != nil {
+		return fmt.Errorf("failed recording migration: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToGetAffectedRows, err)
+	} else if rows == 0 {
+		return migrations.ErrMigrationNotFound
+	}
+	return nil
+}
+
+func (p *sqlDriver) UpdateChecksum(ctx context.Context, id string, checksum string) error {
+	result, err := p.exec(ctx, "UPDATE %s SET checksum = $1 WHERE id = $2", checksum, id)
+	if err != nil {
+		return fmt.Errorf("failed updating migration checksum: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToGetAffectedRows, err)
+	} else if rows == 0 {
+		return migrations.ErrMigrationNotFound
+	}
+	return nil
+}
+
+// exec formats the query with the quoted table name and runs it, binding the `$n` placeholders to the dialect, within the
+// transaction carried by the context, if any. Queries failing because the database is busy are retried.
+func (p *sqlDriver) exec(ctx context.Context, query string, args ...interface{}) (result sql.Result, err error) {
+	query = p.dialect.rebind(fmt.Sprintf(query, p.dialect.table(p.table)))
+	err = p.dialect.retry(ctx, func() error {
+		result, err = execerFromContext(ctx, p.db).ExecContext(ctx, query, args...)
+		return err
+	})
+	return result, err
+}
+
+// exists runs the query, that counts the tables matching the migrations table, binding the `$n` placeholders to the
+// dialect.
+func (p *sqlDriver) exists(ctx context.Context, query string, args ...interface{}) (bool, error) {
+	var count int
+	err := p.query(ctx, p.dialect.rebind(query), args, func(rs *sql.Rows) error {
+		if rs.Next() {
+			return rs.Scan(&count)
+		}
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed checking whether the migrations table exists: %w", err)
+	}
+	return count > 0, nil
+}
+
+// query runs the query, within the transaction carried by the context, if any, calling scan with its rows. Queries
+// failing because the database is busy, while running or while reading their rows, are retried.
+func (p *sqlDriver) query(ctx context.Context, query string, args []interface{}, scan func(rs *sql.Rows) error) error {
+	return p.dialect.retry(ctx, func() error {
+		rs, err := execerFromContext(ctx, p.db).QueryContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = rs.Close()
+		}()
+
+		err = scan(rs)
+		if err != nil {
+			return err
+		}
+		return rs.Err()
+	})
+}
+
 func (p *sqlDriver) generateLockID() (int64, error) {
 	h := murmur3.New64()
 	if _, err := h.Write([]byte(p.databaseName)); err != nil {
@@ -0,0 +1,41 @@
+package drivers
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneric_Exists(t *testing.T) {
+	tests := []struct {
+		name      string
+		tableName string
+		wantArgs  []driver.Value
+	}{
+		{"should fold an unquoted mixed-case name", "MyMigrations", []driver.Value{"mymigrations"}},
+		{"should fold an unquoted mixed-case schema and name", "Ops.MyMigrations", []driver.Value{"ops", "mymigrations"}},
+		{"should keep a quoted name", "Ops.My-Migrations", []driver.Value{"ops", "My-Migrations"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			db, conn := newFakeDB(t, fakeResponse{
+				match:   "information_schema.tables",
+				columns: []string{""},
+				rows:    [][]driver.Value{{int64(1)}},
+			})
+
+			d, err := NewGenericDriver(db, WithTableName(tt.tableName))
+			require.NoError(t, err)
+
+			exists, err := d.Exists(ctx)
+			require.NoError(t, err)
+			assert.True(t, exists)
+			require.Len(t, conn.queries, 1)
+			assert.Equal(t, tt.wantArgs, conn.queries[0].args)
+		})
+	}
+}
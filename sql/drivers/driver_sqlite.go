@@ -0,0 +1,281 @@
+package drivers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/jamillosantos/migrations/v2"
+)
+
+const (
+	sqliteDefaultDatabaseName = "main"
+	sqliteLockPollInterval    = 100 * time.Millisecond
+	// sqliteLockHeartbeats is how many times the lock is refreshed within its expiration.
+	sqliteLockHeartbeats = 6
+)
+
+// DefaultLockExpiration is the time after which a SQLite lock that was not refreshed is taken over. See
+// WithLockExpiration.
+const DefaultLockExpiration = 30 * time.Second
+
+// ErrLockLost is returned when the lock held by the instance was taken over by another one, as when it was not
+// refreshed within its expiration.
+var ErrLockLost = errors.New("the migrations lock was lost")
+
+// sqliteDialect uses the `?` placeholders and column types of SQLite.
+//
+// SQLITE_BUSY and SQLITE_LOCKED errors are detected by their message, so this package does not depend on the cgo
+// based github.com/mattn/go-sqlite3.
+var sqliteDialect = dialect{
+	placeholder: func(_ int) string {
+		return "?"
+	},
+	quote: quoteDouble,
+	columns: []column{
+		{"id", "TEXT PRIMARY KEY"},
+		{"dirty", "BOOLEAN DEFAULT TRUE"},
+		{"applied_at", "TIMESTAMP"},
+		{"execution_time_ms", "INTEGER"},
+		{"checksum", "TEXT"},
+		{"description", "TEXT"},
+	},
+	isBusy: isSQLiteBusy,
+}
+
+func isSQLiteBusy(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "database is locked") ||
+		strings.Contains(msg, "database table is locked") ||
+		strings.Contains(msg, "SQLITE_BUSY")
+}
+
+func isSQLiteUniqueViolation(err error) bool {
+	return strings.Contains(err.Error(), "UNIQUE constraint failed")
+}
+
+type sqliteDriver struct {
+	sqlDriver
+	lockExpiration time.Duration
+	// locker is the lock held by the driver, if any.
+	locker *sqliteLocker
+}
+
+// newSQLite creates the driver for SQLite. As a SQLite database is a single file, the database name is optional and
+// defaults to "main".
+func newSQLite(db DB, options ...Option) (Driver, error) {
+	opts := driverOpts{
+		TableName: DefaultMigrationsTableName,
+	}
+
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	if opts.DatabaseName == "" {
+		opts.DatabaseName = sqliteDefaultDatabaseName
+	}
+	if opts.LockExpiration <= 0 {
+		opts.LockExpiration = DefaultLockExpiration
+	}
+
+	d, err := newSQLDriver(db, sqliteDialect, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &sqliteDriver{sqlDriver: d, lockExpiration: opts.LockExpiration}, nil
+}
+
+// Exists reports whether the migrations table exists, looking it up in the sqlite_master of its schema.
+func (p *sqliteDriver) Exists(ctx context.Context) (bool, error) {
+	schema := sqliteDefaultDatabaseName
+	if p.table.schema != "" {
+		schema = p.table.schema
+	}
+	return p.exists(ctx, "SELECT count(*) FROM "+p.dialect.quote(schema)+".sqlite_master WHERE type = 'table' AND name = $1", p.table.name)
+}
+
+// sqliteLocker is the migrations.Unlocker implementation for SQLite. SQLite has no advisory locks, and holding a write
+// transaction would block the migrations themselves, so the lock is a single row of the `<table>_lock` table, owned by
+// the token of the instance that inserted it.
+//
+// While the lock is held, its acquired_at is refreshed by a heartbeat and by the bookkeeping of every migration, within
+// the transaction of the migration, which would block the heartbeat. A lock that was not refreshed for longer than the
+// lock expiration was left behind by a process that died, so it is taken over by the next instance. The instance whose
+// lock was taken over fails its next bookkeeping with ErrLockLost.
+type sqliteLocker struct {
+	driver *sqliteDriver
+	owner  string
+	lost   atomic.Bool
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+func (p *sqliteLocker) Unlock(ctx context.Context) error {
+	close(p.stop)
+	<-p.done
+	p.driver.locker = nil
+
+	if p.lost.Load() {
+		return ErrLockLost
+	}
+	err := p.driver.lockExec(ctx, "DELETE FROM %s WHERE id = 1 AND owner = $1", p.owner)
+	if err != nil {
+		return fmt.Errorf("failed unlocking migration: %w", err)
+	}
+	return nil
+}
+
+// heartbeat refreshes the acquired_at of the lock until Unlock is called, or the lock is lost.
+func (p *sqliteLocker) heartbeat() {
+	defer close(p.done)
+
+	ticker := time.NewTicker(p.driver.lockExpiration / sqliteLockHeartbeats)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			// A failed refresh, as when a migration holds a write transaction, is retried by the next tick. The
+			// migration refreshes the lock itself.
+			err := p.refresh(context.Background(), p.driver.db)
+			if errors.Is(err, ErrLockLost) {
+				return
+			}
+		}
+	}
+}
+
+// refresh updates the acquired_at of the lock, failing with ErrLockLost when it is not owned by the instance anymore.
+func (p *sqliteLocker) refresh(ctx context.Context, db Execer) error {
+	if p.lost.Load() {
+		return ErrLockLost
+	}
+	query := p.driver.dialect.rebind(fmt.Sprintf("UPDATE %s SET acquired_at = $1 WHERE id = 1 AND owner = $2", p.driver.dialect.table(p.driver.table.withSuffix("_lock"))))
+	return p.driver.dialect.retry(ctx, func() error {
+		result, err := db.ExecContext(ctx, query, time.Now().UnixMilli(), p.owner)
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			p.lost.Store(true)
+			return ErrLockLost
+		}
+		return nil
+	})
+}
+
+// refreshLock refreshes the lock held by the driver, if any, within the transaction carried by the context. It fails
+// with ErrLockLost when another instance took the lock over, so the migration is not recorded.
+func (p *sqliteDriver) refreshLock(ctx context.Context) error {
+	if p.locker == nil {
+		return nil
+	}
+	err := p.locker.refresh(ctx, execerFromContext(ctx, p.db))
+	if err != nil {
+		return fmt.Errorf("failed refreshing the lock: %w", err)
+	}
+	return nil
+}
+
+func (p *sqliteDriver) Add(ctx context.Context, id string) error {
+	if err := p.refreshLock(ctx); err != nil {
+		return err
+	}
+	return p.sqlDriver.Add(ctx, id)
+}
+
+func (p *sqliteDriver) Remove(ctx context.Context, id string) error {
+	if err := p.refreshLock(ctx); err != nil {
+		return err
+	}
+	return p.sqlDriver.Remove(ctx, id)
+}
+
+func (p *sqliteDriver) StartMigration(ctx context.Context, id string) error {
+	if err := p.refreshLock(ctx); err != nil {
+		return err
+	}
+	return p.sqlDriver.StartMigration(ctx, id)
+}
+
+func (p *sqliteDriver) FinishMigration(ctx context.Context, id string) error {
+	if err := p.refreshLock(ctx); err != nil {
+		return err
+	}
+	return p.sqlDriver.FinishMigration(ctx, id)
+}
+
+func (p *sqliteDriver) RecordMigration(ctx context.Context, entry migrations.HistoryEntry) error {
+	if err := p.refreshLock(ctx); err != nil {
+		return err
+	}
+	return p.sqlDriver.RecordMigration(ctx, entry)
+}
+
+// Lock inserts the row of the lock table, waiting while another instance of the migration system holds it. A lock
+// whose holder stopped refreshing it, as when its process died, is taken over after the lock expiration (see
+// WithLockExpiration).
+func (p *sqliteDriver) Lock(ctx context.Context) (migrations.Unlocker, error) {
+	err := p.lockExec(ctx, "CREATE TABLE IF NOT EXISTS %s (id INTEGER PRIMARY KEY CHECK (id = 1), owner TEXT NOT NULL, acquired_at INTEGER)")
+	if err != nil {
+		return nil, fmt.Errorf("failed creating the lock table: %w", err)
+	}
+
+	owner, err := newLockOwner()
+	if err != nil {
+		return nil, fmt.Errorf("failed locking database: %w", err)
+	}
+
+	for {
+		err = p.lockExec(ctx, "INSERT INTO %s (id, owner, acquired_at) VALUES (1, $1, $2)", owner, time.Now().UnixMilli())
+		if err == nil {
+			locker := &sqliteLocker{driver: p, owner: owner, stop: make(chan struct{}), done: make(chan struct{})}
+			p.locker = locker
+			go locker.heartbeat()
+			return locker, nil
+		}
+		if !isSQLiteUniqueViolation(err) {
+			return nil, fmt.Errorf("failed locking database: %w", err)
+		}
+
+		err = p.lockExec(ctx, "DELETE FROM %s WHERE id = 1 AND acquired_at < $1", time.Now().Add(-p.lockExpiration).UnixMilli())
+		if err != nil {
+			return nil, fmt.Errorf("failed expiring the lock: %w", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w: %w", ErrLockNotAcquired, ctx.Err())
+		case <-time.After(sqliteLockPollInterval):
+		}
+	}
+}
+
+// lockExec runs the query, formatted with the name of the lock table, outside of any transaction carried by the
+// context, as the lock must be visible to other instances right away.
+func (p *sqliteDriver) lockExec(ctx context.Context, query string, args ...interface{}) (err error) {
+	query = p.dialect.rebind(fmt.Sprintf(query, p.dialect.table(p.table.withSuffix("_lock"))))
+	return p.dialect.retry(ctx, func() error {
+		_, err = p.db.ExecContext(ctx, query, args...)
+		return err
+	})
+}
+
+func newLockOwner() (string, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(token), nil
+}
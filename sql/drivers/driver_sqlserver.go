@@ -0,0 +1,142 @@
+package drivers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jamillosantos/migrations/v2"
+)
+
+// sqlServerDialect uses the `@pN` placeholders and column types of Microsoft SQL Server, which has no `text` primary
+// keys nor `bool` columns.
+var sqlServerDialect = dialect{
+	placeholder: func(n int) string {
+		return "@p" + strconv.Itoa(n)
+	},
+	quote: func(identifier string) string {
+		return quoteIdentifier(identifier, "[", "]")
+	},
+	columns: []column{
+		{"id", "NVARCHAR(255) NOT NULL PRIMARY KEY"},
+		{"dirty", "BIT DEFAULT 1"},
+		{"applied_at", "DATETIME2 NULL"},
+		{"execution_time_ms", "BIGINT"},
+		{"checksum", "NVARCHAR(64)"},
+		{"description", "NVARCHAR(MAX)"},
+	},
+}
+
+type sqlServerDriver struct {
+	sqlDriver
+}
+
+func newSQLServer(db DB, options ...Option) (Driver, error) {
+	opts := driverOpts{
+		TableName: DefaultMigrationsTableName,
+	}
+
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	if opts.Ctx == nil {
+		opts.Ctx = context.Background()
+	}
+
+	if opts.DatabaseName == "" {
+		rows, err := db.QueryContext(opts.Ctx, "SELECT DB_NAME()")
+		if err != nil {
+			return nil, fmt.Errorf("error obtaining current database name: %w", err)
+		}
+		defer func() {
+			_ = rows.Close()
+		}()
+
+		var databaseName sql.NullString
+		if rows.Next() {
+			err = rows.Scan(&databaseName)
+			if err != nil {
+				return nil, fmt.Errorf("error scanning current database name: %w", err)
+			}
+		}
+		if !databaseName.Valid || databaseName.String == "" {
+			return nil, ErrMissingDatabaseName
+		}
+		opts.DatabaseName = databaseName.String
+	}
+
+	d, err := newSQLDriver(db, sqlServerDialect, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlServerDriver{d}, nil
+}
+
+// Create creates the migrations table, if it does not exist, and adds the columns missing in tables created by older
+// versions. T-SQL has no `CREATE TABLE IF NOT EXISTS`, so the table existence is checked with OBJECT_ID.
+func (p *sqlServerDriver) Create(ctx context.Context) error {
+	definitions := make([]string, len(p.dialect.columns))
+	for i, column := range p.dialect.columns {
+		definitions[i] = column.name + " " + column.definition
+	}
+	_, err := p.exec(ctx, "IF OBJECT_ID(N'%[1]s', N'U') IS NULL CREATE TABLE %[1]s ("+strings.Join(definitions, ", ")+")")
+	if err != nil {
+		return err
+	}
+	return p.upgrade(ctx)
+}
+
+// Exists reports whether the migrations table exists, using OBJECT_ID as Create does.
+func (p *sqlServerDriver) Exists(ctx context.Context) (bool, error) {
+	return p.exists(ctx, "SELECT count(*) WHERE OBJECT_ID($1, N'U') IS NOT NULL", p.dialect.table(p.table))
+}
+
+// sqlServerLocker is the migrations.Unlocker implementation for SQL Server. The application lock is owned by a
+// transaction that is kept open until Unlock is called.
+type sqlServerLocker struct {
+	db       TXExecer
+	resource string
+}
+
+func (p *sqlServerLocker) Unlock(ctx context.Context) error {
+	_, err := p.db.ExecContext(ctx, "EXEC sp_releaseapplock @Resource = @p1, @LockOwner = 'Transaction'", p.resource)
+	if err != nil {
+		_ = p.db.Rollback()
+		return fmt.Errorf("failed unlocking migration: %w", err)
+	}
+	_ = p.db.Commit()
+	return nil
+}
+
+// Lock acquires an exclusive application lock, using sp_getapplock, whose resource is derived from the database and
+// table names. It waits until the lock is released by any other instance of the migration system.
+func (p *sqlServerDriver) Lock(ctx context.Context) (migrations.Unlocker, error) {
+	lockID, err := p.generateLockID()
+	if err != nil {
+		return nil, fmt.Errorf("failed locking database: %w", err)
+	}
+	resource := fmt.Sprintf("migrations:%d", lockID)
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed starting transaction for locking: %w", err)
+	}
+
+	// sp_getapplock returns 0 or 1 when the lock is granted, and a negative number otherwise.
+	var result int64
+	err = tx.QueryRowContext(ctx, "DECLARE @result INT; "+
+		"EXEC @result = sp_getapplock @Resource = @p1, @LockMode = 'Exclusive', @LockOwner = 'Transaction', @LockTimeout = -1; "+
+		"SELECT @result", resource).Scan(&result)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, fmt.Errorf("failed locking database: %w", err)
+	}
+	if result < 0 {
+		_ = tx.Rollback()
+		return nil, fmt.Errorf("%w: sp_getapplock returned %d", ErrLockNotAcquired, result)
+	}
+	return &sqlServerLocker{db: tx, resource: resource}, nil
+}
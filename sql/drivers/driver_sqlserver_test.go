@@ -0,0 +1,170 @@
+package drivers
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jamillosantos/migrations/v2"
+)
+
+func TestSQLServer_newSQLServer(t *testing.T) {
+	t.Run("should obtain the database name", func(t *testing.T) {
+		db, conn := newFakeDB(t, fakeResponse{
+			match:   "SELECT DB_NAME()",
+			columns: []string{""},
+			rows:    [][]driver.Value{{"reports"}},
+		})
+
+		d, err := newSQLServer(db)
+		require.NoError(t, err)
+		assert.Equal(t, "reports", d.(*sqlServerDriver).databaseName)
+		assert.Equal(t, []string{"SELECT DB_NAME()"}, conn.Queries())
+	})
+
+	t.Run("should fail when there is no current database", func(t *testing.T) {
+		db, _ := newFakeDB(t, fakeResponse{
+			match:   "SELECT DB_NAME()",
+			columns: []string{""},
+			rows:    [][]driver.Value{{nil}},
+		})
+
+		_, err := newSQLServer(db)
+		assert.ErrorIs(t, err, ErrMissingDatabaseName)
+	})
+}
+
+func TestSQLServer_Create(t *testing.T) {
+	t.Run("should create the table with T-SQL types", func(t *testing.T) {
+		ctx := context.Background()
+		db, conn := newFakeDB(t, fakeResponse{
+			match:   "WHERE 1 = 0",
+			columns: []string{"id", "dirty", "applied_at", "execution_time_ms", "checksum", "description"},
+		})
+
+		d, err := newSQLServer(db, WithDatabaseName("reports"))
+		require.NoError(t, err)
+
+		require.NoError(t, d.(TableDriver).Create(ctx))
+		assert.Equal(t, []string{
+			"IF OBJECT_ID(N'_migrations', N'U') IS NULL CREATE TABLE _migrations (id NVARCHAR(255) NOT NULL PRIMARY KEY, dirty BIT DEFAULT 1, applied_at DATETIME2 NULL, execution_time_ms BIGINT, checksum NVARCHAR(64), description NVARCHAR(MAX))",
+			"SELECT * FROM _migrations WHERE 1 = 0",
+		}, conn.Queries())
+	})
+
+	t.Run("should add the columns missing in older tables", func(t *testing.T) {
+		ctx := context.Background()
+		db, conn := newFakeDB(t, fakeResponse{
+			match:   "WHERE 1 = 0",
+			columns: []string{"id", "dirty", "applied_at", "execution_time_ms"},
+		})
+
+		d, err := newSQLServer(db, WithDatabaseName("reports"), WithTableName("migrations"))
+		require.NoError(t, err)
+
+		require.NoError(t, d.(TableDriver).Create(ctx))
+		assert.Equal(t, []string{
+			"ALTER TABLE migrations ADD checksum NVARCHAR(64)",
+			"ALTER TABLE migrations ADD description NVARCHAR(MAX)",
+		}, conn.Queries()[2:])
+	})
+}
+
+func TestSQLServer_Queries(t *testing.T) {
+	t.Run("should use the @pN placeholders", func(t *testing.T) {
+		ctx := context.Background()
+		db, conn := newFakeDB(t)
+
+		d, err := newSQLServer(db, WithDatabaseName("reports"))
+		require.NoError(t, err)
+
+		appliedAt := time.Date(2025, 1, 9, 1, 12, 42, 0, time.UTC)
+		require.NoError(t, d.Add(ctx, "1"))
+		require.NoError(t, d.(TableDriver).RecordMigration(ctx, migrations.HistoryEntry{
+			ID:            "1",
+			Description:   "create table",
+			Checksum:      "checksum",
+			AppliedAt:     appliedAt,
+			ExecutionTime: 2 * time.Second,
+		}))
+
+		require.Len(t, conn.queries, 2)
+		assert.Equal(t, fakeQuery{
+			query: "INSERT INTO _migrations (id, dirty) VALUES (@p1, @p2)",
+			args:  []driver.Value{"1", true},
+		}, conn.queries[0])
+		assert.Equal(t, fakeQuery{
+			query: "UPDATE _migrations SET dirty = @p1, applied_at = @p2, execution_time_ms = @p3, checksum = @p4, description = @p5 WHERE id = @p6",
+			args:  []driver.Value{false, appliedAt, int64(2000), "checksum", "create table", "1"},
+		}, conn.queries[1])
+	})
+}
+
+func TestSQLServer_Lock(t *testing.T) {
+	t.Run("should lock and unlock using an application lock", func(t *testing.T) {
+		ctx := context.Background()
+		db, conn := newFakeDB(t, fakeResponse{
+			match:   "sp_getapplock",
+			columns: []string{""},
+			rows:    [][]driver.Value{{int64(0)}},
+		})
+
+		d, err := newSQLServer(db, WithDatabaseName("reports"))
+		require.NoError(t, err)
+
+		unlocker, err := d.Lock(ctx)
+		require.NoError(t, err)
+		require.NoError(t, unlocker.Unlock(ctx))
+
+		lockID, err := d.(*sqlServerDriver).generateLockID()
+		require.NoError(t, err)
+		resource := fmt.Sprintf("migrations:%d", lockID)
+
+		require.Len(t, conn.queries, 2)
+		assert.Contains(t, conn.queries[0].query, "EXEC @result = sp_getapplock @Resource = @p1, @LockMode = 'Exclusive'")
+		assert.Equal(t, []driver.Value{resource}, conn.queries[0].args)
+		assert.Equal(t, fakeQuery{
+			query: "EXEC sp_releaseapplock @Resource = @p1, @LockOwner = 'Transaction'",
+			args:  []driver.Value{resource},
+		}, conn.queries[1])
+		assert.Equal(t, 1, conn.commits)
+	})
+
+	t.Run("should fail when the lock is not granted", func(t *testing.T) {
+		ctx := context.Background()
+		db, conn := newFakeDB(t, fakeResponse{
+			match:   "sp_getapplock",
+			columns: []string{""},
+			rows:    [][]driver.Value{{int64(-3)}},
+		})
+
+		d, err := newSQLServer(db, WithDatabaseName("reports"))
+		require.NoError(t, err)
+
+		_, err = d.Lock(ctx)
+		assert.ErrorIs(t, err, ErrLockNotAcquired)
+		assert.Equal(t, 1, conn.rollbacks)
+	})
+
+	t.Run("should fail when the lock query fails", func(t *testing.T) {
+		ctx := context.Background()
+		wantErr := errors.New("random error")
+		db, conn := newFakeDB(t, fakeResponse{
+			match: "sp_getapplock",
+			err:   wantErr,
+		})
+
+		d, err := newSQLServer(db, WithDatabaseName("reports"))
+		require.NoError(t, err)
+
+		_, err = d.Lock(ctx)
+		assert.ErrorIs(t, err, wantErr)
+		assert.Equal(t, 1, conn.rollbacks)
+	})
+}
@@ -0,0 +1,157 @@
+package drivers
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeQuery is a query received by the fakeConn.
+type fakeQuery struct {
+	query string
+	args  []driver.Value
+}
+
+// fakeResponse is the scripted response of the fakeConn for the queries that contain match.
+type fakeResponse struct {
+	match   string
+	columns []string
+	rows    [][]driver.Value
+	err     error
+}
+
+// fakeConn is a driver.Conn that records the queries it receives and answers them with the first response whose match
+// is contained in the query. Queries without a response succeed, affecting a single row.
+type fakeConn struct {
+	mu        sync.Mutex
+	queries   []fakeQuery
+	responses []fakeResponse
+	commits   int
+	rollbacks int
+}
+
+func newFakeDB(t *testing.T, responses ...fakeResponse) (*sql.DB, *fakeConn) {
+	conn := &fakeConn{responses: responses}
+	db := sql.OpenDB(&fakeConnector{conn: conn})
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+	return db, conn
+}
+
+func (c *fakeConn) Queries() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	result := make([]string, len(c.queries))
+	for i, q := range c.queries {
+		result[i] = q.query
+	}
+	return result
+}
+
+func (c *fakeConn) respond(query string, args []driver.NamedValue) fakeResponse {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	c.queries = append(c.queries, fakeQuery{query: query, args: values})
+	for _, r := range c.responses {
+		if strings.Contains(query, r.match) {
+			return r
+		}
+	}
+	return fakeResponse{}
+}
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	r := c.respond(query, args)
+	if r.err != nil {
+		return nil, r.err
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	r := c.respond(query, args)
+	if r.err != nil {
+		return nil, r.err
+	}
+	return &fakeRows{columns: r.columns, rows: r.rows}, nil
+}
+
+func (c *fakeConn) Prepare(_ string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported")
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return &fakeTx{conn: c}, nil
+}
+
+type fakeTx struct {
+	conn *fakeConn
+}
+
+func (tx *fakeTx) Commit() error {
+	tx.conn.mu.Lock()
+	defer tx.conn.mu.Unlock()
+	tx.conn.commits++
+	return nil
+}
+
+func (tx *fakeTx) Rollback() error {
+	tx.conn.mu.Lock()
+	defer tx.conn.mu.Unlock()
+	tx.conn.rollbacks++
+	return nil
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.columns
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+type fakeConnector struct {
+	conn *fakeConn
+}
+
+func (c *fakeConnector) Connect(_ context.Context) (driver.Conn, error) {
+	return c.conn, nil
+}
+
+func (c *fakeConnector) Driver() driver.Driver {
+	return fakeDriver{}
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(_ string) (driver.Conn, error) {
+	return nil, errors.New("use the fakeConnector")
+}
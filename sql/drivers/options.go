@@ -2,12 +2,15 @@ package drivers
 
 import (
 	"context"
+	"time"
 )
 
 type driverOpts struct {
-	Ctx          context.Context
-	DatabaseName string
-	TableName    string
+	Ctx            context.Context
+	DatabaseName   string
+	TableName      string
+	CreateSchema   bool
+	LockExpiration time.Duration
 }
 
 type Option func(*driverOpts)
@@ -23,3 +26,28 @@ func WithContext(ctx context.Context) Option {
 		opts.Ctx = ctx
 	}
 }
+
+// WithTableName sets the name of the table that stores the applied migrations. It can be qualified by its schema (eg.
+// `ops._migrations`), and it is quoted when needed.
+func WithTableName(name string) Option {
+	return func(opts *driverOpts) {
+		opts.TableName = name
+	}
+}
+
+// WithCreateSchema makes the drivers that support schemas (Postgres) create the schema of a schema-qualified table name
+// (eg. `ops._migrations`), when it does not exist, along with the migrations table.
+func WithCreateSchema() Option {
+	return func(opts *driverOpts) {
+		opts.CreateSchema = true
+	}
+}
+
+// WithLockExpiration sets the time after which a lock that was not refreshed is taken over by another instance, for the
+// drivers whose locks are rows of a table (SQLite). It defaults to DefaultLockExpiration. The lock is refreshed by the
+// bookkeeping of every migration, so it should be longer than the longest migration.
+func WithLockExpiration(expiration time.Duration) Option {
+	return func(opts *driverOpts) {
+		opts.LockExpiration = expiration
+	}
+}
@@ -0,0 +1,90 @@
+package drivers
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var ErrInvalidTableName = errors.New("invalid table name")
+
+// plainIdentifierRegexp matches the identifiers that are interpolated as they are. Any other identifier is quoted, so
+// table names valid before quoting was supported keep referring to the same table.
+var plainIdentifierRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// tableName is a table name, optionally qualified by its schema.
+type tableName struct {
+	schema string
+	name   string
+}
+
+// ValidateTableName checks whether the name is a valid, optionally schema-qualified (`schema.table`), table name.
+func ValidateTableName(name string) error {
+	_, err := parseTableName(name)
+	return err
+}
+
+func parseTableName(name string) (tableName, error) {
+	parts := strings.Split(name, ".")
+	if len(parts) > 2 {
+		return tableName{}, fmt.Errorf("%w: %q has more than a schema and a table", ErrInvalidTableName, name)
+	}
+	for _, part := range parts {
+		err := validateIdentifier(part)
+		if err != nil {
+			return tableName{}, fmt.Errorf("%w: %q %s", ErrInvalidTableName, name, err.Error())
+		}
+	}
+
+	if len(parts) == 1 {
+		return tableName{name: parts[0]}, nil
+	}
+	return tableName{schema: parts[0], name: parts[1]}, nil
+}
+
+// validateIdentifier rejects the identifiers that are empty or have quotes and control characters. Quotes are not
+// needed, as identifiers are quoted by the dialect when needed, and rejecting them rules out any SQL injection. `$` is
+// rejected as well, as the `$n` placeholders of the queries are rebound after the table name is interpolated.
+func validateIdentifier(identifier string) error {
+	if identifier == "" {
+		return errors.New("has an empty identifier")
+	}
+	for _, r := range identifier {
+		if unicode.IsControl(r) || strings.ContainsRune("\"'`[];$", r) {
+			return fmt.Errorf("has the invalid character %q", r)
+		}
+	}
+	return nil
+}
+
+// withSuffix returns the name of a table, in the same schema, whose name has the given suffix.
+func (t tableName) withSuffix(suffix string) tableName {
+	return tableName{schema: t.schema, name: t.name + suffix}
+}
+
+// folded returns the name as stored by the databases that fold unquoted identifiers to lower case, as Postgres does.
+// Quoted identifiers are kept as they are.
+func (t tableName) folded() tableName {
+	fold := func(identifier string) string {
+		if plainIdentifierRegexp.MatchString(identifier) {
+			return strings.ToLower(identifier)
+		}
+		return identifier
+	}
+	return tableName{schema: fold(t.schema), name: fold(t.name)}
+}
+
+// quoteIdentifier quotes the identifier with the given quotes, when it is not a plain identifier.
+func quoteIdentifier(identifier, open, close string) string {
+	if plainIdentifierRegexp.MatchString(identifier) {
+		return identifier
+	}
+	return open + identifier + close
+}
+
+// quoteDouble quotes identifiers with double quotes, the ANSI SQL standard used by Postgres and SQLite.
+func quoteDouble(identifier string) string {
+	return quoteIdentifier(identifier, `"`, `"`)
+}
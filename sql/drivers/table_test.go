@@ -0,0 +1,60 @@
+package drivers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseTableName(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    tableName
+		wantErr bool
+	}{
+		{name: "table", input: "_migrations", want: tableName{name: "_migrations"}},
+		{name: "schema and table", input: "ops._migrations", want: tableName{schema: "ops", name: "_migrations"}},
+		{name: "name needing quotes", input: "my-schema.Migrations", want: tableName{schema: "my-schema", name: "Migrations"}},
+		{name: "empty", input: "", wantErr: true},
+		{name: "empty schema", input: "._migrations", wantErr: true},
+		{name: "too many parts", input: "db.ops._migrations", wantErr: true},
+		{name: "quotes", input: `"_migrations"`, wantErr: true},
+		{name: "injection", input: "_migrations; DROP TABLE users", wantErr: true},
+		{name: "control characters", input: "_migrations\n", wantErr: true},
+		{name: "placeholder", input: "ops.t$1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTableName(tt.input)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidTableName)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_dialect_table(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect dialect
+		table   tableName
+		want    string
+	}{
+		{name: "plain", dialect: sqlDialect, table: tableName{name: "_migrations"}, want: "_migrations"},
+		{name: "schema", dialect: sqlDialect, table: tableName{schema: "ops", name: "_migrations"}, want: "ops._migrations"},
+		{name: "postgres", dialect: sqlDialect, table: tableName{schema: "my-ops", name: "Migrations"}, want: `"my-ops".Migrations`},
+		{name: "sqlite", dialect: sqliteDialect, table: tableName{name: "my migrations"}, want: `"my migrations"`},
+		{name: "mysql", dialect: mysqlDialect, table: tableName{schema: "my-ops", name: "_migrations"}, want: "`my-ops`._migrations"},
+		{name: "sql server", dialect: sqlServerDialect, table: tableName{schema: "ops", name: "my migrations"}, want: "ops.[my migrations]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.dialect.table(tt.table))
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package drivers
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jamillosantos/migrations/v2/internal/sqltx"
+)
+
+// Execer abstracts the methods shared by the database and a transaction that are used by the drivers to run their
+// queries.
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// ContextWithTx returns a copy of ctx that carries the given transaction. Drivers run their queries within the
+// transaction carried by the context, when there is one.
+func ContextWithTx(ctx context.Context, tx *sql.Tx) context.Context {
+	return sqltx.WithTx(ctx, tx)
+}
+
+// TxFromContext returns the transaction carried by the context, or nil if there is none.
+func TxFromContext(ctx context.Context) *sql.Tx {
+	return sqltx.FromContext(ctx)
+}
+
+// execerFromContext returns the transaction carried by the context, falling back to the given db.
+func execerFromContext(ctx context.Context, db Execer) Execer {
+	if tx := TxFromContext(ctx); tx != nil {
+		return tx
+	}
+	return db
+}
@@ -15,21 +15,44 @@ import (
 //	var migrationsFS embed.FS
 //
 //	source, err := sql.SourceFromFS(dbGetter, migrationsFS, "migrations")
-func SourceFromFS(dbGetter func() DBExecer, fs fs.ReadDirFS, folder string) (migrations.Source, error) {
-	return &source{
-		dbGetter: dbGetter,
-
-		fs:     fs,
-		folder: folder,
-	}, nil
+func SourceFromFS(dbGetter func() DBExecer, fs fs.ReadDirFS, folder string, options ...SourceOption) (migrations.Source, error) {
+	return newSource(dbGetter, sourceRoot{fs: fs, folder: folder}, options...)
 }
 
 // SourceFromDirectory creates a new source based on the provided folder in the disk.
-func SourceFromDirectory(dbGetter func() DBExecer, folder string) (migrations.Source, error) {
-	return &source{
+func SourceFromDirectory(dbGetter func() DBExecer, folder string, options ...SourceOption) (migrations.Source, error) {
+	return newSource(dbGetter, sourceRoot{fs: os.DirFS(folder).(fs.ReadDirFS), folder: ".", dir: folder}, options...)
+}
+
+func newSource(dbGetter func() DBExecer, root sourceRoot, options ...SourceOption) (*source, error) {
+	var opts sourceOpts
+	for _, opt := range options {
+		err := opt(&opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	s := &source{
 		dbGetter: dbGetter,
 
-		fs:     os.DirFS(folder).(fs.ReadDirFS),
-		folder: folder,
-	}, nil
+		roots:     []sourceRoot{root},
+		recursive: opts.recursive,
+
+		template: &migrationTemplate{},
+	}
+	for _, extraFolder := range opts.folders {
+		s.roots = append(s.roots, sourceRoot{fs: root.fs, folder: extraFolder, dir: root.dir})
+	}
+	s.roots = append(s.roots, opts.roots...)
+	if opts.template != nil {
+		s.template, s.renderAll = opts.template, true
+	}
+	if len(opts.placeholders) > 0 {
+		s.placeholders = &placeholders{lookups: opts.placeholders}
+	}
+	if opts.splitStatements {
+		s.splitter = &statementSplitter{backslashEscapes: opts.backslashEscapes, hashComments: opts.hashComments}
+	}
+	return s, nil
 }
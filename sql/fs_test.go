@@ -0,0 +1,27 @@
+package sql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSourceFromDirectory(t *testing.T) {
+	t.Run("should load the migrations of the folder", func(t *testing.T) {
+		ctx := context.Background()
+
+		source, err := SourceFromDirectory(nil, "testdata/case1")
+		require.NoError(t, err)
+
+		repo, err := source.Load(ctx)
+		require.NoError(t, err)
+
+		list, err := repo.List(ctx)
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		assert.Equal(t, "20211015015442", list[0].ID())
+		assert.Equal(t, "20211015015556", list[1].ID())
+	})
+}
@@ -3,10 +3,12 @@
 package sql_test
 
 import (
+	"bytes"
 	"context"
 	stdsql "database/sql"
 	"embed"
 	"testing"
+	"testing/fstest"
 
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/require"
@@ -27,6 +29,9 @@ var (
 
 	//go:embed testdata/case3/*.sql
 	case3Migrations embed.FS
+
+	//go:embed testdata/case4/*.sql
+	case4Migrations embed.FS
 )
 
 type migrationCase string
@@ -35,9 +40,10 @@ const (
 	migrationCase1 migrationCase = "case1"
 	migrationCase2 migrationCase = "case2"
 	migrationCase3 migrationCase = "case3"
+	migrationCase4 migrationCase = "case4"
 )
 
-func migrate(t *testing.T, db *stdsql.DB, migrationCase migrationCase) error {
+func migrate(t *testing.T, db *stdsql.DB, migrationCase migrationCase, opts ...migrations.MigrateOption) error {
 	t.Helper()
 
 	ctx := context.Background()
@@ -60,6 +66,9 @@ func migrate(t *testing.T, db *stdsql.DB, migrationCase migrationCase) error {
 	case migrationCase3:
 		fs = case3Migrations
 		caseFolder = "case3"
+	case migrationCase4:
+		fs = case4Migrations
+		caseFolder = "case4"
 	}
 
 	source, err := sql.SourceFromFS(func() sql.DBExecer {
@@ -70,7 +79,8 @@ func migrate(t *testing.T, db *stdsql.DB, migrationCase migrationCase) error {
 	target, err := sql.NewTarget(db, sql.WithDriverOptions(drivers.WithDatabaseName("testdb")))
 	require.NoError(t, err)
 
-	_, err = migrations.Migrate(ctx, source, target, migrations.WithRunnerOptions(migrations.WithReporter(reporters.NewZapReporter(logger))))
+	opts = append([]migrations.MigrateOption{migrations.WithRunnerOptions(migrations.WithReporter(reporters.NewZapReporter(logger)))}, opts...)
+	_, err = migrations.Migrate(ctx, source, target, opts...)
 	return err
 }
 
@@ -89,6 +99,37 @@ func TestMigrate_Integration(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("should print the case 2 plan without applying it", func(t *testing.T) {
+		db := createDBConnection(t)
+		err := migrate(t, db, migrationCase1)
+		require.NoError(t, err)
+
+		var output bytes.Buffer
+		err = migrate(t, db, migrationCase2, migrations.WithDryRun(&output))
+		require.NoError(t, err)
+		require.Contains(t, output.String(), "ALTER TABLE customers ADD COLUMN birthday date;")
+
+		// As nothing was applied, the case 2 should still be pending.
+		output.Reset()
+		err = migrate(t, db, migrationCase2, migrations.WithDryRun(&output))
+		require.NoError(t, err)
+		require.Contains(t, output.String(), "20211015045556")
+	})
+
+	t.Run("should print the case 1 plan without creating the target", func(t *testing.T) {
+		db := createDBConnection(t)
+
+		var output bytes.Buffer
+		err := migrate(t, db, migrationCase1, migrations.WithDryRun(&output))
+		require.NoError(t, err)
+		require.Contains(t, output.String(), "-- dry run: ")
+
+		// Neither the migrations table nor the table used for locking were created.
+		var count int
+		require.NoError(t, db.QueryRow("SELECT count(*) FROM sqlite_master WHERE type = 'table'").Scan(&count))
+		require.Zero(t, count)
+	})
+
 	t.Run("should fail running the case 3 after case 1 and case 2", func(t *testing.T) {
 		db := createDBConnection(t)
 		err := migrate(t, db, migrationCase1)
@@ -98,6 +139,51 @@ func TestMigrate_Integration(t *testing.T) {
 		err = migrate(t, db, migrationCase3)
 		require.ErrorIs(t, err, migrations.ErrStaleMigrationDetected)
 	})
+
+	t.Run("should run the case 4 after case 1 and case 2 allowing out of order migrations", func(t *testing.T) {
+		db := createDBConnection(t)
+		err := migrate(t, db, migrationCase1)
+		require.NoError(t, err)
+		err = migrate(t, db, migrationCase2)
+		require.NoError(t, err)
+		err = migrate(t, db, migrationCase4, migrations.WithPlanner(migrations.MigratePlannerWithOptions(migrations.AllowOutOfOrder())))
+		require.NoError(t, err)
+	})
+}
+
+func TestMigrate_Integration_Checksums(t *testing.T) {
+	ctx := context.Background()
+
+	db := createDBConnection(t)
+	files := fstest.MapFS{
+		"migrations/20211015015442_create_customers_table.sql": &fstest.MapFile{Data: []byte("CREATE TABLE customers (id int)")},
+	}
+
+	source, err := sql.SourceFromFS(func() sql.DBExecer {
+		return db
+	}, files, "migrations")
+	require.NoError(t, err)
+
+	target, err := sql.NewTarget(db, sql.WithDriverOptions(drivers.WithDatabaseName("testdb")))
+	require.NoError(t, err)
+
+	planner := migrations.WithPlanner(migrations.MigratePlannerWithOptions(migrations.VerifyChecksums()))
+
+	_, err = migrations.Migrate(ctx, source, target, planner)
+	require.NoError(t, err)
+
+	// Changes an already applied migration.
+	files["migrations/20211015015442_create_customers_table.sql"].Data = []byte("CREATE TABLE customers (id bigint)")
+
+	_, err = migrations.Migrate(ctx, source, target, planner)
+	require.ErrorIs(t, err, migrations.ErrChecksumMismatch)
+
+	repaired, err := migrations.RepairChecksums(ctx, source, target)
+	require.NoError(t, err)
+	require.Len(t, repaired, 1)
+
+	_, err = migrations.Migrate(ctx, source, target, planner)
+	require.NoError(t, err)
 }
 
 func createDBConnection(t *testing.T) *stdsql.DB {
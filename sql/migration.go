@@ -2,9 +2,13 @@ package sql
 
 import (
 	"context"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/hex"
 	"fmt"
 
 	"github.com/jamillosantos/migrations/v2"
+	"github.com/jamillosantos/migrations/v2/sql/drivers"
 )
 
 type migrationSQL struct {
@@ -18,6 +22,12 @@ type migrationSQL struct {
 	doFileContent   string
 	undoFile        string
 	undoFileContent string
+	// doStatements and undoStatements are the statements of the files, when the source splits them. When nil, the
+	// whole file is executed as a single query.
+	doStatements   []string
+	undoStatements []string
+	noTransaction  bool
+	metadata       migrations.MigrationMetadata
 }
 
 // ID identifies the migration. Through the ID, all the sorting is done.
@@ -28,7 +38,7 @@ func (migration *migrationSQL) ID() string {
 // String will return a representation of the migration into a string format
 // for user identification.
 func (migration *migrationSQL) String() string {
-	if migration.CanUndo() {
+	if migration.CanUndo() && migration.undoFile != migration.doFile {
 		return fmt.Sprintf("[%s,%s]", migration.doFile, migration.undoFile)
 	}
 	return fmt.Sprintf("[%s]", migration.doFile)
@@ -63,19 +73,85 @@ func (migration *migrationSQL) SetPrevious(value migrations.Migration) migration
 	return migration
 }
 
-func (migration *migrationSQL) executeSQL(ctx context.Context, sql string) error {
-	db := migration.dbGetter()
+func (migration *migrationSQL) executeSQL(ctx context.Context, sql string, statements []string) error {
+	var db DBExecer
+	if tx := TxFromContext(ctx); tx != nil {
+		// The migration is part of a transaction started by the Target.
+		db = tx
+	} else {
+		db = migration.dbGetter()
+	}
+
+	// The statements of a whole file are not retried, as the ones before a failed statement were executed.
+	if statements == nil {
+		_, err := db.ExecContext(ctx, sql)
+		if err != nil {
+			return migrations.NewQueryError(err, sql)
+		}
+		return nil
+	}
 
-	_, err := db.ExecContext(ctx, sql)
-	if err != nil {
-		return migrations.NewQueryError(err, sql)
+	// A statement that fails because the database is busy was not executed, so it is safe to run it again.
+	dbWithDriver := migration.dbWithDriver()
+	for i, statement := range statements {
+		err := drivers.RetryBusy(ctx, dbWithDriver, func() error {
+			_, err := db.ExecContext(ctx, statement)
+			return err
+		})
+		if err != nil {
+			return migrations.NewStatementQueryError(err, statement, i+1)
+		}
 	}
 	return nil
 }
 
+// dbWithDriver returns the database of the migration, when it exposes its driver, which tells whether a failed
+// statement is retried.
+func (migration *migrationSQL) dbWithDriver() interface{ Driver() driver.Driver } {
+	if migration.dbGetter == nil {
+		return nil
+	}
+	db, ok := migration.dbGetter().(interface{ Driver() driver.Driver })
+	if !ok {
+		return nil
+	}
+	return db
+}
+
 // Do will execute the migration.
 func (migration *migrationSQL) Do(ctx context.Context) error {
-	return migration.executeSQL(ctx, migration.doFileContent)
+	return migration.executeSQL(ctx, migration.doFileContent, migration.doStatements)
+}
+
+// NoTransaction reports if the migration cannot run inside of a transaction. That is declared by the
+// `-- migrations: no-transaction` directive in the header of any of its files.
+func (migration *migrationSQL) NoTransaction() bool {
+	return migration.noTransaction
+}
+
+// Metadata returns the metadata declared by the `-- migrations: timeout=<duration>`, `-- migrations: tags=<tag>,...`
+// and `-- migrations: requires=<id>,...` directives in the header of its files.
+func (migration *migrationSQL) Metadata() migrations.MigrationMetadata {
+	return migration.metadata
+}
+
+// Script returns the SQL executed by the given action.
+func (migration *migrationSQL) Script(action migrations.ActionType) string {
+	if action == migrations.ActionTypeUndo {
+		return migration.undoFileContent
+	}
+	return migration.doFileContent
+}
+
+// Checksum returns the SHA-256 checksum, hex encoded, of the content of the migration files.
+func (migration *migrationSQL) Checksum() string {
+	h := sha256.New()
+	_, _ = h.Write([]byte(migration.doFileContent))
+	if migration.undoFile != "" {
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write([]byte(migration.undoFileContent))
+	}
+	return hex.EncodeToString(h.Sum(nil))
 }
 
 // CanUndo is a flag that mark this flag as undoable.
@@ -85,5 +161,5 @@ func (migration *migrationSQL) CanUndo() bool {
 
 // Undo will undo the migration.
 func (migration *migrationSQL) Undo(ctx context.Context) error {
-	return migration.executeSQL(ctx, migration.undoFileContent)
+	return migration.executeSQL(ctx, migration.undoFileContent, migration.undoStatements)
 }
@@ -0,0 +1,97 @@
+package sql
+
+import (
+	"errors"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrUndefinedPlaceholder = errors.New("undefined placeholder")
+
+	// placeholderRegexp matches the `${NAME}` placeholders, and their escaped form `$${NAME}`.
+	placeholderRegexp = regexp.MustCompile(`\$?\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+)
+
+// UndefinedPlaceholdersError is returned by the source Load when a migration file has placeholders that are not defined
+// by any of its lookups. It matches ErrUndefinedPlaceholder with errors.Is.
+type UndefinedPlaceholdersError struct {
+	file         string
+	placeholders []string
+}
+
+// File is the migration file with the undefined placeholders.
+func (err *UndefinedPlaceholdersError) File() string {
+	return err.file
+}
+
+// Placeholders are the names, sorted, of the undefined placeholders.
+func (err *UndefinedPlaceholdersError) Placeholders() []string {
+	return err.placeholders
+}
+
+func (err *UndefinedPlaceholdersError) Is(target error) bool {
+	return target == ErrUndefinedPlaceholder
+}
+
+func (err *UndefinedPlaceholdersError) Error() string {
+	return err.file + ": " + ErrUndefinedPlaceholder.Error() + ": " + strings.Join(err.placeholders, ", ")
+}
+
+// placeholderLookup returns the value of a placeholder and whether it is defined.
+type placeholderLookup func(name string) (string, bool)
+
+// placeholders replaces the `${NAME}` placeholders of the migration files by the values returned by the first lookup
+// that defines them.
+type placeholders struct {
+	lookups []placeholderLookup
+}
+
+func mapLookup(values map[string]string) placeholderLookup {
+	return func(name string) (string, bool) {
+		value, ok := values[name]
+		return value, ok
+	}
+}
+
+// envLookup looks the placeholders up in the environment variables.
+func envLookup(name string) (string, bool) {
+	return os.LookupEnv(name)
+}
+
+func (p *placeholders) lookup(name string) (string, bool) {
+	for _, lookup := range p.lookups {
+		if value, ok := lookup(name); ok {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// replace replaces the placeholders of the content. `$${NAME}` is kept as the literal `${NAME}`.
+func (p *placeholders) replace(file, content string) (string, error) {
+	undefined := make(map[string]struct{})
+	result := placeholderRegexp.ReplaceAllStringFunc(content, func(placeholder string) string {
+		if strings.HasPrefix(placeholder, "$$") {
+			return placeholder[1:]
+		}
+		name := placeholder[2 : len(placeholder)-1]
+		value, ok := p.lookup(name)
+		if !ok {
+			undefined[name] = struct{}{}
+		}
+		return value
+	})
+
+	if len(undefined) > 0 {
+		names := make([]string, 0, len(undefined))
+		for name := range undefined {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return "", &UndefinedPlaceholdersError{file: file, placeholders: names}
+	}
+	return result, nil
+}
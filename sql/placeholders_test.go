@@ -0,0 +1,62 @@
+package sql
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jamillosantos/migrations/v2"
+)
+
+func TestSource_Placeholders(t *testing.T) {
+	files := fstest.MapFS{
+		"1_create_customers.sql": {Data: []byte("CREATE TABLE ${SCHEMA}.customers (id int) TABLESPACE ${TABLESPACE};\n-- $${SCHEMA} is kept")},
+	}
+
+	t.Run("should replace the placeholders from the map and the environment", func(t *testing.T) {
+		t.Setenv("TABLESPACE", "fast")
+		t.Setenv("SCHEMA", "ignored")
+
+		ctx := context.Background()
+		s, err := SourceFromFS(nil, files, ".", WithPlaceholders(map[string]string{"SCHEMA": "ops"}), WithEnvPlaceholders())
+		require.NoError(t, err)
+
+		repo, err := s.Load(ctx)
+		require.NoError(t, err)
+		list, err := repo.List(ctx)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, "CREATE TABLE ops.customers (id int) TABLESPACE fast;\n-- ${SCHEMA} is kept", list[0].(*migrationSQL).Script(migrations.ActionTypeDo))
+	})
+
+	t.Run("should list the undefined placeholders", func(t *testing.T) {
+		s, err := SourceFromFS(nil, fstest.MapFS{
+			"1_create_customers.sql": {Data: []byte("CREATE TABLE ${SCHEMA}.customers (id int) TABLESPACE ${TABLESPACE}; GRANT SELECT ON ${SCHEMA}.customers TO ${ROLE};")},
+		}, ".", WithPlaceholders(map[string]string{"SCHEMA": "ops"}))
+		require.NoError(t, err)
+
+		_, err = s.Load(context.Background())
+		require.ErrorIs(t, err, ErrUndefinedPlaceholder)
+		var pErr *UndefinedPlaceholdersError
+		require.True(t, errors.As(err, &pErr))
+		assert.Equal(t, "1_create_customers.sql", pErr.File())
+		assert.Equal(t, []string{"ROLE", "TABLESPACE"}, pErr.Placeholders())
+		assert.EqualError(t, err, "1_create_customers.sql: undefined placeholder: ROLE, TABLESPACE")
+	})
+
+	t.Run("should keep the placeholders without the option", func(t *testing.T) {
+		ctx := context.Background()
+		s, err := SourceFromFS(nil, files, ".")
+		require.NoError(t, err)
+
+		repo, err := s.Load(ctx)
+		require.NoError(t, err)
+		m, err := repo.ByID("1")
+		require.NoError(t, err)
+		assert.Contains(t, m.(*migrationSQL).Script(migrations.ActionTypeDo), "${SCHEMA}.customers")
+	})
+}
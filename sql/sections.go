@@ -0,0 +1,77 @@
+package sql
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	sectionUpMarker   = "-- +up"
+	sectionDownMarker = "-- +down"
+)
+
+var (
+	ErrInvalidSections = errors.New("invalid migration sections")
+	// ErrMixedMigrationStyles is returned when a migration is declared by a single file with sections and by separate
+	// do/undo files at the same time.
+	ErrMixedMigrationStyles = errors.New("migration mixes a single file with sections and separate do/undo files")
+)
+
+// fileSections are the sections of a single file migration.
+type fileSections struct {
+	up   string
+	down string
+	// hasDown reports whether the file has the `-- +down` section, even if empty, so the migration can be undone.
+	hasDown bool
+}
+
+// parseSections splits the content of a single file migration into its `-- +up` and `-- +down` sections. The comments
+// before the `-- +up` marker (eg. directives) are kept at the beginning of the up section.
+//
+// It returns ok false when the content has no section markers, so it is a migration without undo.
+func parseSections(content string) (result fileSections, ok bool, err error) {
+	var (
+		preamble              strings.Builder
+		preambleHasStatements bool
+		sections              = map[string]*strings.Builder{}
+		current               *strings.Builder
+	)
+	for _, line := range strings.SplitAfter(content, "\n") {
+		marker := strings.ToLower(strings.TrimSpace(line))
+		if marker == sectionUpMarker || marker == sectionDownMarker {
+			if sections[marker] != nil {
+				return fileSections{}, false, fmt.Errorf("%w: %s is declared more than once", ErrInvalidSections, marker)
+			}
+			if marker == sectionDownMarker && sections[sectionUpMarker] == nil {
+				return fileSections{}, false, fmt.Errorf("%w: %s must come after %s", ErrInvalidSections, sectionDownMarker, sectionUpMarker)
+			}
+			current = &strings.Builder{}
+			sections[marker] = current
+			continue
+		}
+
+		if current == nil {
+			if trimmed := strings.TrimSpace(line); trimmed != "" && !strings.HasPrefix(trimmed, "--") {
+				preambleHasStatements = true
+			}
+			preamble.WriteString(line)
+			continue
+		}
+		current.WriteString(line)
+	}
+
+	if len(sections) == 0 {
+		return fileSections{}, false, nil
+	}
+	if preambleHasStatements {
+		// Statements before the first marker would belong to no section.
+		return fileSections{}, false, fmt.Errorf("%w: statements must come after %s", ErrInvalidSections, sectionUpMarker)
+	}
+
+	result.up = preamble.String() + sections[sectionUpMarker].String()
+	if downSection := sections[sectionDownMarker]; downSection != nil {
+		result.down, result.hasDown = downSection.String(), true
+	}
+	return result, true, nil
+}
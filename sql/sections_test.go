@@ -0,0 +1,54 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseSections(t *testing.T) {
+	t.Run("should split the up and down sections", func(t *testing.T) {
+		content := "-- migrations: tags=schema\n\n-- +up\nCREATE TABLE customers (id int);\n-- +down\nDROP TABLE customers;\n"
+
+		got, ok, err := parseSections(content)
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.Equal(t, "-- migrations: tags=schema\n\nCREATE TABLE customers (id int);\n", got.up)
+		assert.Equal(t, "DROP TABLE customers;\n", got.down)
+		assert.True(t, got.hasDown)
+	})
+
+	t.Run("should accept a file without the down section", func(t *testing.T) {
+		got, ok, err := parseSections("-- +Up\nCREATE TABLE customers (id int);")
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.Equal(t, "CREATE TABLE customers (id int);", got.up)
+		assert.False(t, got.hasDown)
+	})
+
+	t.Run("should accept an empty down section", func(t *testing.T) {
+		got, ok, err := parseSections("-- +up\nUPDATE customers SET name = '';\n-- +down\n")
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.Empty(t, got.down)
+		assert.True(t, got.hasDown)
+	})
+
+	t.Run("should report files without sections", func(t *testing.T) {
+		_, ok, err := parseSections("CREATE TABLE customers (id int);")
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("should fail with invalid sections", func(t *testing.T) {
+		for _, content := range []string{
+			"-- +down\nDROP TABLE customers;\n-- +up\nCREATE TABLE customers (id int);",
+			"-- +up\nSELECT 1;\n-- +up\nSELECT 2;",
+			"CREATE TABLE customers (id int);\n-- +up\nSELECT 1;",
+		} {
+			_, _, err := parseSections(content)
+			assert.ErrorIs(t, err, ErrInvalidSections, content)
+		}
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"io"
 	"io/fs"
 	"path"
+	"path/filepath"
 	"regexp"
 	"strings"
 
@@ -16,65 +17,76 @@ import (
 var (
 	ErrInvalidMigrationDirection = errors.New("invalid migration direction")
 
-	migrationFileNameRegexp = regexp.MustCompile(`^(\d+)_(.*?)(\.(do|undo|down|up))?\.sql$`)
+	migrationFileNameRegexp = regexp.MustCompile(`^(\d+)_(.*?)(\.(do|undo|down|up))?\.sql(\.tmpl)?$`)
 )
 
 type source struct {
-	fs     fs.ReadDirFS
-	folder string
+	roots []sourceRoot
+	// recursive makes the migration files of the subdirectories of the roots to be loaded as well.
+	recursive bool
 
 	repo     migrations.Repository
 	dbGetter func() DBExecer
+	// splitter splits the migration files into statements. When nil, files are executed as a single query.
+	splitter *statementSplitter
+	// template renders the `.sql.tmpl` files, and all files when renderAll is set.
+	template  *migrationTemplate
+	renderAll bool
+	// placeholders replaces the `${NAME}` placeholders of the files. When nil, placeholders are not replaced.
+	placeholders *placeholders
+}
+
+// sourceRoot is a folder, of a file system, where the migration files are listed from.
+type sourceRoot struct {
+	fs     fs.ReadDirFS
+	folder string
+	// dir is the directory, in the disk, where the file system is rooted, when it is one.
+	dir string
+	// fsIndex numbers the file systems added by WithFS, starting from 1, so their files can be told apart.
+	fsIndex int
 }
 
 type migration struct {
+	// root is the index of the source root where the files of the migration are.
+	root int
+	// dir is the directory, in the root, where the files of the migration are.
+	dir         string
 	description string
 	doFile      string
 	undoFile    string
+	// singleFile reports whether the do file has no direction in its name, so it can have `-- +up` and `-- +down`
+	// sections.
+	singleFile bool
 }
 
-func (s *source) Load(ctx context.Context) (migrations.Repository, error) {
-	entries, err := fs.ReadDir(s.fs, s.folder)
-	if err != nil {
-		return migrations.Repository{}, fmt.Errorf("failed listing migrations files: %w", err)
+// file returns any of the files of the migration, so it can be reported.
+func (m *migration) file() string {
+	if m.doFile != "" {
+		return m.doFile
 	}
+	return m.undoFile
+}
 
+func (s *source) Load(ctx context.Context) (migrations.Repository, error) {
 	migrationSet := make(map[string]*migration)
-	for _, entry := range entries {
-		id, description, t := parseSQLFile(entry)
-		if id == "" { // Does not match
-			continue
-		}
-
-		migrationEntry := migrationSet[id]
-		if migrationEntry == nil {
-			migrationEntry = &migration{
-				description: description,
-			}
-			migrationSet[id] = migrationEntry
+	for i, root := range s.roots {
+		files, err := root.list(s.recursive)
+		if err != nil {
+			return migrations.Repository{}, fmt.Errorf("failed listing migrations files: %w", err)
 		}
 
-		switch t {
-		case "", "up", "do":
-			if migrationEntry.doFile != "" {
-				// TODO: Improve this error
-				return migrations.Repository{}, fmt.Errorf("migration %s already defined by %s", entry.Name(), migrationEntry.doFile)
-			}
-			migrationEntry.doFile = path.Join(s.folder, entry.Name())
-		case "down", "undo":
-			if migrationEntry.undoFile != "" {
-				// TODO: Improve this error
-				return migrations.Repository{}, fmt.Errorf("migration %s already defined by %s", entry.Name(), migrationEntry.doFile)
+		for _, file := range files {
+			err = addMigrationFile(migrationSet, s.roots, i, file)
+			if err != nil {
+				return migrations.Repository{}, err
 			}
-			migrationEntry.undoFile = path.Join(s.folder, entry.Name())
-		default:
-			return migrations.Repository{}, fmt.Errorf("%w: %s (%s)", ErrInvalidMigrationDirection, t, entry.Name())
 		}
 	}
 
 	for migrationID, migration := range migrationSet {
 		m, err := s.repo.ByID(migrationID)
-		if errors.Is(err, migrations.ErrMigrationNotFound) {
+		isNew := errors.Is(err, migrations.ErrMigrationNotFound)
+		if isNew {
 			// If the migration was not added yet, create the instance and add it.
 			m = &migrationSQL{
 				dbGetter: s.dbGetter,
@@ -86,22 +98,36 @@ func (s *source) Load(ctx context.Context) (migrations.Repository, error) {
 			return migrations.Repository{}, err
 		}
 
-		mSQL := m.(*migrationSQL)
+		mSQL, ok := m.(*migrationSQL)
+		if !ok {
+			// The ID is already taken by a migration added by other means (eg. a code migration).
+			return migrations.Repository{}, migrations.WrapMigrationID(migrations.ErrMigrationAlreadyExists, migrationID)
+		}
 
-		mSQL.doFile = migration.doFile
-		mSQL.doFileContent, err = loadMigrationFile(s.fs, migration.doFile)
+		err = s.loadMigrationFiles(mSQL, migrationID, migration)
+		if err != nil {
+			return migrations.Repository{}, err
+		}
+		mSQL.doStatements, err = s.splitStatements(mSQL.doFile, mSQL.doFileContent)
 		if err != nil {
 			return migrations.Repository{}, err
 		}
-		mSQL.undoFile = migration.undoFile
-		mSQL.undoFileContent, err = loadMigrationFile(s.fs, migration.undoFile)
+		mSQL.undoStatements, err = s.splitStatements(mSQL.undoFile, mSQL.undoFileContent)
 		if err != nil {
 			return migrations.Repository{}, err
 		}
-		err = s.repo.Add(m)
+		directives, err := parseMigrationDirectives(mSQL.doFile, mSQL.doFileContent, mSQL.undoFile, mSQL.undoFileContent)
 		if err != nil {
 			return migrations.Repository{}, err
 		}
+		mSQL.noTransaction = directives.noTransaction
+		mSQL.metadata = directives.metadata
+		if isNew {
+			err = s.repo.Add(m)
+			if err != nil {
+				return migrations.Repository{}, err
+			}
+		}
 
 		// Interrupt the loop if the context is done.
 		select {
@@ -114,10 +140,171 @@ func (s *source) Load(ctx context.Context) (migrations.Repository, error) {
 	return s.repo, nil
 }
 
+// display returns the path of the file, listed from the root, as reported by errors. The files of the directories in the
+// disk are reported by their path in the disk, the ones of the file systems added by WithFS by their number.
+func (root sourceRoot) display(file string) string {
+	if root.dir != "" {
+		return filepath.Join(root.dir, filepath.FromSlash(file))
+	}
+	if root.fsIndex > 0 {
+		return fmt.Sprintf("%s (WithFS #%d)", file, root.fsIndex)
+	}
+	return file
+}
+
+// list lists the files in the folder of the root, in lexical order. When recursive is set, the files of the
+// subdirectories are listed as well.
+func (root sourceRoot) list(recursive bool) ([]sourceFile, error) {
+	files := make([]sourceFile, 0)
+	err := fs.WalkDir(root.fs, root.folder, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if file != root.folder && !recursive {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		files = append(files, sourceFile{path: file, entry: entry})
+		return nil
+	})
+	return files, err
+}
+
+// sourceFile is a file listed from a source root.
+type sourceFile struct {
+	path  string
+	entry fs.DirEntry
+}
+
+// addMigrationFile adds the file, listed from the given root, to the migration it belongs to. A migration can only be
+// declared by files in the same directory, so the same ID in other directories, or roots, is reported as a
+// ErrMigrationAlreadyExists naming both files.
+func addMigrationFile(migrationSet map[string]*migration, roots []sourceRoot, root int, file sourceFile) error {
+	id, description, t := parseSQLFile(file.entry)
+	if id == "" { // Does not match
+		return nil
+	}
+
+	dir := path.Dir(file.path)
+	migrationEntry := migrationSet[id]
+	if migrationEntry == nil {
+		migrationEntry = &migration{
+			root:        root,
+			dir:         dir,
+			description: description,
+		}
+		migrationSet[id] = migrationEntry
+	} else if migrationEntry.root != root || migrationEntry.dir != dir {
+		return migrationAlreadyExists(id, roots[migrationEntry.root].display(migrationEntry.file()), roots[root].display(file.path))
+	}
+
+	switch t {
+	case "", "up", "do":
+		if migrationEntry.doFile != "" {
+			return migrationAlreadyExists(id, roots[root].display(migrationEntry.doFile), roots[root].display(file.path))
+		}
+		migrationEntry.doFile = file.path
+		migrationEntry.singleFile = t == ""
+	case "down", "undo":
+		if migrationEntry.undoFile != "" {
+			return migrationAlreadyExists(id, roots[root].display(migrationEntry.undoFile), roots[root].display(file.path))
+		}
+		migrationEntry.undoFile = file.path
+	default:
+		return fmt.Errorf("%w: %s (%s)", ErrInvalidMigrationDirection, t, file.path)
+	}
+	return nil
+}
+
+func migrationAlreadyExists(migrationID, file, otherFile string) error {
+	return migrations.WrapMigrationID(fmt.Errorf("%w: %s and %s", migrations.ErrMigrationAlreadyExists, file, otherFile), migrationID)
+}
+
 func (s *source) Add(_ context.Context, migration migrations.Migration) error {
 	return s.repo.Add(migration)
 }
 
+// loadMigrationFiles loads the content of the do and undo files of the migration. A single file migration, with the
+// `-- +up` and `-- +down` sections, has the same file as do and undo.
+func (s *source) loadMigrationFiles(mSQL *migrationSQL, migrationID string, migration *migration) error {
+	var err error
+	fsys := s.roots[migration.root].fs
+	mSQL.doFile = migration.doFile
+	mSQL.doFileContent, err = s.loadFile(fsys, migration.doFile)
+	if err != nil {
+		return err
+	}
+
+	if migration.singleFile {
+		sections, ok, err := parseSections(mSQL.doFileContent)
+		if err != nil {
+			return fmt.Errorf("%s: %w", migration.doFile, err)
+		}
+		if ok {
+			if migration.undoFile != "" {
+				return migrations.WrapMigrationID(fmt.Errorf("%w: %s has sections, but %s exists", ErrMixedMigrationStyles, migration.doFile, migration.undoFile), migrationID)
+			}
+			mSQL.doFileContent = sections.up
+			mSQL.undoFile, mSQL.undoFileContent = "", ""
+			if sections.hasDown {
+				mSQL.undoFile, mSQL.undoFileContent = migration.doFile, sections.down
+			}
+			return nil
+		}
+	}
+
+	mSQL.undoFile = migration.undoFile
+	mSQL.undoFileContent, err = s.loadFile(fsys, migration.undoFile)
+	return err
+}
+
+// loadFile loads the content of the migration file, rendering it when it is a template, and replacing its placeholders.
+func (s *source) loadFile(fsys fs.ReadDirFS, file string) (string, error) {
+	content, err := loadMigrationFile(fsys, file)
+	if err != nil || file == "" {
+		return content, err
+	}
+
+	if s.renderAll || isTemplateFile(file) {
+		content, err = s.template.render(file, content)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", file, err)
+		}
+	}
+
+	if s.placeholders != nil {
+		return s.placeholders.replace(file, content)
+	}
+	return content, nil
+}
+
+// parseMigrationDirectives parses and merges the directives of the do and undo files of a migration.
+func parseMigrationDirectives(doFile, doContent, undoFile, undoContent string) (fileDirectives, error) {
+	doDirectives, err := parseFileDirectives(doContent)
+	if err != nil {
+		return fileDirectives{}, fmt.Errorf("%s: %w", doFile, err)
+	}
+	undoDirectives, err := parseFileDirectives(undoContent)
+	if err != nil {
+		return fileDirectives{}, fmt.Errorf("%s: %w", undoFile, err)
+	}
+	return doDirectives.merge(undoDirectives), nil
+}
+
+// splitStatements splits the content of the file into statements. It returns nil when splitting is not enabled.
+func (s *source) splitStatements(file, content string) ([]string, error) {
+	if s.splitter == nil || file == "" {
+		return nil, nil
+	}
+	statements, err := s.splitter.split(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed splitting the statements of %s: %w", file, err)
+	}
+	return statements, nil
+}
+
 func loadMigrationFile(fs fs.ReadDirFS, file string) (string, error) {
 	if file == "" {
 		// does not have migration
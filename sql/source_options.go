@@ -0,0 +1,126 @@
+package sql
+
+import (
+	"io/fs"
+	"os"
+	"text/template"
+)
+
+type sourceOpts struct {
+	splitStatements  bool
+	backslashEscapes bool
+	hashComments     bool
+	template         *migrationTemplate
+	placeholders     []placeholderLookup
+	recursive        bool
+	folders          []string
+	roots            []sourceRoot
+}
+
+// SourceOption configures the sources created by SourceFromFS and SourceFromDirectory.
+type SourceOption func(opts *sourceOpts) error
+
+// WithStatementSplitting splits the migration files into their individual statements, executing them one by one. This
+// is needed by drivers that do not accept multiple statements in a single query (eg. MySQL without
+// `multiStatements=true`).
+//
+// Semicolons inside of quotes, comments and Postgres dollar quotes are understood. Statements that have semicolons on
+// their own (eg. triggers) can be wrapped by the `-- +migrate StatementBegin` and `-- +migrate StatementEnd` markers.
+func WithStatementSplitting() SourceOption {
+	return func(opts *sourceOpts) error {
+		opts.splitStatements = true
+		return nil
+	}
+}
+
+// WithBackslashEscapes makes the statement splitting handle backslashes as escapes inside of strings ('it\'s'), as
+// MySQL does by default.
+func WithBackslashEscapes() SourceOption {
+	return func(opts *sourceOpts) error {
+		opts.backslashEscapes = true
+		return nil
+	}
+}
+
+// WithHashComments makes the statement splitting handle `#` as the start of a comment that runs until the end of the
+// line, as MySQL does.
+func WithHashComments() SourceOption {
+	return func(opts *sourceOpts) error {
+		opts.hashComments = true
+		return nil
+	}
+}
+
+// WithTemplate renders the migration files through text/template, with the given data and functions, before they are
+// executed. The rendered SQL is what is executed, shown by dry runs, checksummed and reported by errors.
+//
+// Files with the `.sql.tmpl` extension are always rendered, even without this option.
+func WithTemplate(data map[string]any, funcs template.FuncMap) SourceOption {
+	return func(opts *sourceOpts) error {
+		opts.template = &migrationTemplate{data: data, funcs: funcs}
+		return nil
+	}
+}
+
+// WithPlaceholders replaces the `${NAME}` placeholders of the migration files by the given values. Placeholders that
+// are not defined fail the load with an UndefinedPlaceholdersError. `$${NAME}` is kept as the literal `${NAME}`.
+//
+// When combined with WithEnvPlaceholders, placeholders are looked up in the order the options are given.
+func WithPlaceholders(values map[string]string) SourceOption {
+	return func(opts *sourceOpts) error {
+		opts.placeholders = append(opts.placeholders, mapLookup(values))
+		return nil
+	}
+}
+
+// WithEnvPlaceholders replaces the `${NAME}` placeholders of the migration files by the value of the environment
+// variables with the same name. See WithPlaceholders.
+func WithEnvPlaceholders() SourceOption {
+	return func(opts *sourceOpts) error {
+		opts.placeholders = append(opts.placeholders, envLookup)
+		return nil
+	}
+}
+
+// WithRecursive loads the migration files of the subdirectories as well, so migrations can be organized by year or by
+// module (eg. `migrations/2025/...` and `migrations/billing/...`). All the files of a migration must be in the same
+// directory.
+func WithRecursive() SourceOption {
+	return func(opts *sourceOpts) error {
+		opts.recursive = true
+		return nil
+	}
+}
+
+// WithFolders loads the migration files of other folders, of the same file system of the source, as well. The same ID
+// declared in more than one folder fails the load with migrations.ErrMigrationAlreadyExists.
+func WithFolders(folders ...string) SourceOption {
+	return func(opts *sourceOpts) error {
+		opts.folders = append(opts.folders, folders...)
+		return nil
+	}
+}
+
+// WithFS loads the migration files of a folder in another file system as well. See WithFolders.
+func WithFS(fsys fs.ReadDirFS, folder string) SourceOption {
+	return func(opts *sourceOpts) error {
+		fsIndex := 1
+		for _, root := range opts.roots {
+			if root.fsIndex > 0 {
+				fsIndex++
+			}
+		}
+		opts.roots = append(opts.roots, sourceRoot{fs: fsys, folder: folder, fsIndex: fsIndex})
+		return nil
+	}
+}
+
+// WithDirectories loads the migration files of other folders in the disk as well. See WithFolders.
+func WithDirectories(folders ...string) SourceOption {
+	return func(opts *sourceOpts) error {
+		for _, folder := range folders {
+			opts.roots = append(opts.roots, sourceRoot{fs: os.DirFS(folder).(fs.ReadDirFS), folder: ".", dir: folder})
+		}
+		return nil
+	}
+}
@@ -0,0 +1,164 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing/fstest"
+
+	_ "github.com/mattn/go-sqlite3"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jamillosantos/migrations/v2"
+	"github.com/jamillosantos/migrations/v2/sql/drivers"
+)
+
+// busyDB fails the first executions as a busy SQLite database does.
+type busyDB struct {
+	*sql.DB
+	failures int
+	queries  []string
+}
+
+func (db *busyDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	db.queries = append(db.queries, query)
+	if db.failures > 0 {
+		db.failures--
+		return nil, errors.New("database is locked")
+	}
+	return db.DB.ExecContext(ctx, query, args...)
+}
+
+var _ = Describe("Statement splitting", func() {
+	var (
+		db     *sql.DB
+		target *Target
+
+		ctx context.Context
+	)
+
+	newSource := func(files fstest.MapFS) migrations.Source {
+		s, err := SourceFromFS(func() DBExecer {
+			return db
+		}, files, ".", WithStatementSplitting())
+		Expect(err).ToNot(HaveOccurred())
+		return s
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		newDB, err := sql.Open("sqlite3", ":memory:")
+		Expect(err).ToNot(HaveOccurred(), "should open the database")
+		newDB.SetMaxOpenConns(1)
+		db = newDB
+
+		newTarget, err := NewTarget(db, WithDriverOptions(drivers.WithDatabaseName("test")))
+		Expect(err).ToNot(HaveOccurred(), "should create the target")
+		target = newTarget
+	})
+
+	AfterEach(func() {
+		Expect(db.Close()).To(Succeed())
+	})
+
+	It("should execute the statements one by one", func() {
+		source := newSource(fstest.MapFS{
+			"1_create_customers.sql": {Data: []byte(`CREATE TABLE customers (id int, name text);
+INSERT INTO customers VALUES (1, 'a;b');
+-- +migrate StatementBegin
+CREATE TRIGGER customers_name AFTER INSERT ON customers
+BEGIN
+  UPDATE customers SET name = 'trigger' WHERE id = NEW.id;
+END;
+-- +migrate StatementEnd
+INSERT INTO customers VALUES (2, 'c');`)},
+		})
+
+		_, err := migrations.Migrate(ctx, source, target)
+		Expect(err).ToNot(HaveOccurred())
+
+		var names []string
+		rows, err := db.QueryContext(ctx, "SELECT name FROM customers ORDER BY id")
+		Expect(err).ToNot(HaveOccurred())
+		for rows.Next() {
+			var name string
+			Expect(rows.Scan(&name)).To(Succeed())
+			names = append(names, name)
+		}
+		Expect(rows.Close()).To(Succeed())
+		Expect(names).To(Equal([]string{"a;b", "trigger"}))
+	})
+
+	It("should report the failing statement", func() {
+		source := newSource(fstest.MapFS{
+			"1_create_customers.sql": {Data: []byte("CREATE TABLE customers (id int);\nINSERT INTO unknown VALUES (1);\n")},
+		})
+
+		_, err := migrations.Migrate(ctx, source, target)
+		Expect(err).To(HaveOccurred())
+
+		var queryErr migrations.StatementQueryError
+		Expect(errors.As(err, &queryErr)).To(BeTrue())
+		Expect(queryErr.Statement()).To(Equal(2))
+		Expect(queryErr.Query()).To(Equal("INSERT INTO unknown VALUES (1)"))
+		Expect(err.Error()).To(ContainSubstring("statement 2"))
+	})
+
+	It("should fail loading a file that cannot be split", func() {
+		source := newSource(fstest.MapFS{
+			"1_create_customers.sql": {Data: []byte("CREATE TABLE customers (name text default 'a);")},
+		})
+
+		_, err := source.Load(ctx)
+		Expect(err).To(MatchError(ErrUnterminatedStatement))
+		Expect(err.Error()).To(ContainSubstring("1_create_customers.sql"))
+	})
+	When("the database is busy", func() {
+		newMigration := func(db *busyDB, statements []string) *migrationSQL {
+			return &migrationSQL{
+				dbGetter: func() DBExecer {
+					return db
+				},
+				id:            "1",
+				doFile:        "1_create_customers.sql",
+				doFileContent: "CREATE TABLE customers (id int);\nINSERT INTO customers VALUES (1);",
+				doStatements:  statements,
+			}
+		}
+
+		It("should retry the statement that failed", func() {
+			busy := &busyDB{DB: db, failures: 1}
+			m := newMigration(busy, []string{"CREATE TABLE customers (id int)", "INSERT INTO customers VALUES (1)"})
+
+			Expect(m.Do(ctx)).To(Succeed())
+			Expect(busy.queries).To(Equal([]string{
+				"CREATE TABLE customers (id int)",
+				"CREATE TABLE customers (id int)",
+				"INSERT INTO customers VALUES (1)",
+			}))
+		})
+
+		It("should not retry a whole file, whose first statements may have been executed", func() {
+			busy := &busyDB{DB: db, failures: 1}
+			m := newMigration(busy, nil)
+
+			err := m.Do(ctx)
+			Expect(err).To(MatchError(ContainSubstring("database is locked")))
+			Expect(busy.queries).To(HaveLen(1))
+		})
+
+		It("should not retry the statements of other databases", func() {
+			busy := &busyDB{DB: db, failures: 1}
+			m := newMigration(busy, []string{"CREATE TABLE customers (id int)"})
+			m.dbGetter = func() DBExecer {
+				// Hides the driver of the database.
+				return struct{ DBExecer }{busy}
+			}
+
+			err := m.Do(ctx)
+			Expect(err).To(MatchError(ContainSubstring("database is locked")))
+		})
+	})
+})
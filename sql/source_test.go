@@ -3,12 +3,20 @@
 package sql
 
 import (
+	"context"
 	"errors"
 	"io"
+	"os"
+	"path/filepath"
 	"testing"
+	"testing/fstest"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
+
+	"github.com/jamillosantos/migrations/v2"
 )
 
 func Test_parseSQLFile(t *testing.T) {
@@ -122,3 +130,207 @@ func Test_loadMigrationFile(t *testing.T) {
 		assert.ErrorIs(t, err, wantErr)
 	})
 }
+
+func Test_parseDirectives(t *testing.T) {
+	t.Run("should parse the directives from the header", func(t *testing.T) {
+		content := "-- Creates an index\n\n-- migrations: no-transaction\nCREATE INDEX CONCURRENTLY idx ON customers (name);\n-- migrations: ignored"
+
+		assert.Equal(t, []string{"no-transaction"}, parseDirectives(content))
+	})
+
+	t.Run("should return no directives when there is no header", func(t *testing.T) {
+		assert.Empty(t, parseDirectives("CREATE TABLE customers (id int);"))
+	})
+}
+
+func Test_parseFileDirectives(t *testing.T) {
+	t.Run("should parse the metadata directives", func(t *testing.T) {
+		content := "-- migrations: no-transaction\n-- migrations: timeout=30s\n-- migrations: tags=data, slow\n-- migrations: requires=1,2\n-- migrations: requires=3\nUPDATE customers SET name = 'a';"
+
+		got, err := parseFileDirectives(content)
+		require.NoError(t, err)
+		assert.True(t, got.noTransaction)
+		assert.Equal(t, migrations.MigrationMetadata{
+			Timeout:  30 * time.Second,
+			Tags:     []string{"data", "slow"},
+			Requires: []string{"1", "2", "3"},
+		}, got.metadata)
+	})
+
+	t.Run("should parse the directives after other comments of the header", func(t *testing.T) {
+		got, err := parseFileDirectives("-- Creates an index\n\n-- migrations: no-transaction\nCREATE INDEX CONCURRENTLY idx ON customers (name);")
+		require.NoError(t, err)
+		assert.True(t, got.noTransaction)
+	})
+
+	t.Run("should merge the directives of the undo file", func(t *testing.T) {
+		do, err := parseFileDirectives("-- migrations: tags=data\n-- migrations: timeout=1m")
+		require.NoError(t, err)
+		undo, err := parseFileDirectives("-- migrations: no-transaction\n-- migrations: tags=slow\n-- migrations: timeout=2m")
+		require.NoError(t, err)
+
+		got := do.merge(undo)
+		assert.True(t, got.noTransaction)
+		assert.Equal(t, time.Minute, got.metadata.Timeout)
+		assert.Equal(t, []string{"data", "slow"}, got.metadata.Tags)
+	})
+
+	t.Run("should fail with an invalid timeout", func(t *testing.T) {
+		_, err := parseFileDirectives("-- migrations: timeout=soon")
+		assert.ErrorIs(t, err, ErrInvalidDirective)
+	})
+
+	t.Run("should fail with an unknown directive", func(t *testing.T) {
+		_, err := parseFileDirectives("-- migrations: no-transactions")
+		assert.ErrorIs(t, err, ErrInvalidDirective)
+	})
+}
+
+func Test_source_Load(t *testing.T) {
+	t.Run("should load a single file migration with sections", func(t *testing.T) {
+		ctx := context.Background()
+		s, err := SourceFromFS(nil, fstest.MapFS{
+			"1_create_customers.sql":    {Data: []byte("-- +up\nCREATE TABLE customers (id int);\n-- +down\nDROP TABLE customers;\n")},
+			"2_add_name.sql":            {Data: []byte("ALTER TABLE customers ADD name text;")},
+			"3_create_orders.up.sql":    {Data: []byte("CREATE TABLE orders (id int);")},
+			"3_create_orders.down.sql":  {Data: []byte("DROP TABLE orders;")},
+			"4_add_created_at.sql":      {Data: []byte("-- +up\nALTER TABLE customers ADD created_at timestamp;")},
+			"5_add_updated_at.sql":      {Data: []byte("ALTER TABLE customers ADD updated_at timestamp;")},
+			"5_add_updated_at.down.sql": {Data: []byte("ALTER TABLE customers DROP updated_at;")},
+			"not_a_migration.sql":       {Data: []byte("SELECT 1;")},
+		}, ".")
+		require.NoError(t, err)
+
+		repo, err := s.Load(ctx)
+		require.NoError(t, err)
+		list, err := repo.List(ctx)
+		require.NoError(t, err)
+		require.Len(t, list, 5)
+
+		m1 := list[0].(*migrationSQL)
+		assert.True(t, m1.CanUndo())
+		assert.Equal(t, "CREATE TABLE customers (id int);\n", m1.Script(migrations.ActionTypeDo))
+		assert.Equal(t, "DROP TABLE customers;\n", m1.Script(migrations.ActionTypeUndo))
+		assert.Equal(t, "[1_create_customers.sql]", m1.String())
+
+		assert.False(t, list[1].CanUndo())
+		assert.True(t, list[2].CanUndo())
+		assert.False(t, list[3].CanUndo())
+		assert.True(t, list[4].CanUndo())
+	})
+
+	t.Run("should fail when a migration mixes both styles", func(t *testing.T) {
+		s, err := SourceFromFS(nil, fstest.MapFS{
+			"1_create_customers.sql":      {Data: []byte("-- +up\nCREATE TABLE customers (id int);\n-- +down\nDROP TABLE customers;\n")},
+			"1_create_customers.down.sql": {Data: []byte("DROP TABLE customers;")},
+		}, ".")
+		require.NoError(t, err)
+
+		_, err = s.Load(context.Background())
+		assert.ErrorIs(t, err, ErrMixedMigrationStyles)
+		assert.ErrorContains(t, err, "1_create_customers.down.sql")
+		var idErr migrations.MigrationIDError
+		require.ErrorAs(t, err, &idErr)
+		assert.Equal(t, "1", idErr.MigrationID())
+	})
+
+	t.Run("should load the migrations of the subdirectories when recursive", func(t *testing.T) {
+		ctx := context.Background()
+		fsys := fstest.MapFS{
+			"migrations/1_create_customers.sql":        {Data: []byte("CREATE TABLE customers (id int);")},
+			"migrations/2025/2_create_orders.up.sql":   {Data: []byte("CREATE TABLE orders (id int);")},
+			"migrations/2025/2_create_orders.down.sql": {Data: []byte("DROP TABLE orders;")},
+			"migrations/billing/3_create_invoices.sql": {Data: []byte("CREATE TABLE invoices (id int);")},
+		}
+
+		s, err := SourceFromFS(nil, fsys, "migrations")
+		require.NoError(t, err)
+		repo, err := s.Load(ctx)
+		require.NoError(t, err)
+		list, err := repo.List(ctx)
+		require.NoError(t, err)
+		assert.Len(t, list, 1)
+
+		s, err = SourceFromFS(nil, fsys, "migrations", WithRecursive())
+		require.NoError(t, err)
+		repo, err = s.Load(ctx)
+		require.NoError(t, err)
+		list, err = repo.List(ctx)
+		require.NoError(t, err)
+		require.Len(t, list, 3)
+		assert.Equal(t, "[migrations/2025/2_create_orders.up.sql,migrations/2025/2_create_orders.down.sql]", list[1].String())
+		assert.Equal(t, "[migrations/billing/3_create_invoices.sql]", list[2].String())
+	})
+
+	t.Run("should combine multiple folders and file systems", func(t *testing.T) {
+		ctx := context.Background()
+		s, err := SourceFromFS(nil, fstest.MapFS{
+			"core/1_create_customers.sql":   {Data: []byte("CREATE TABLE customers (id int);")},
+			"billing/3_create_invoices.sql": {Data: []byte("CREATE TABLE invoices (id int);")},
+			"ignored/4_create_products.sql": {Data: []byte("CREATE TABLE products (id int);")},
+		}, "core", WithFolders("billing"), WithFS(fstest.MapFS{
+			"2_create_orders.sql": {Data: []byte("CREATE TABLE orders (id int);")},
+		}, "."))
+		require.NoError(t, err)
+
+		repo, err := s.Load(ctx)
+		require.NoError(t, err)
+		list, err := repo.List(ctx)
+		require.NoError(t, err)
+		require.Len(t, list, 3)
+		assert.Equal(t, "CREATE TABLE orders (id int);", list[1].(*migrationSQL).Script(migrations.ActionTypeDo))
+		assert.Equal(t, "[billing/3_create_invoices.sql]", list[2].String())
+	})
+
+	t.Run("should fail when the same ID is declared in different folders", func(t *testing.T) {
+		s, err := SourceFromFS(nil, fstest.MapFS{
+			"migrations/2024/1_create_customers.sql": {Data: []byte("CREATE TABLE customers (id int);")},
+			"migrations/2025/1_create_orders.sql":    {Data: []byte("CREATE TABLE orders (id int);")},
+		}, "migrations", WithRecursive())
+		require.NoError(t, err)
+
+		_, err = s.Load(context.Background())
+		assert.ErrorIs(t, err, migrations.ErrMigrationAlreadyExists)
+		assert.ErrorContains(t, err, "migrations/2024/1_create_customers.sql and migrations/2025/1_create_orders.sql")
+		var idErr migrations.MigrationIDError
+		require.ErrorAs(t, err, &idErr)
+		assert.Equal(t, "1", idErr.MigrationID())
+	})
+
+	t.Run("should name the directories of the same ID declared in different directories", func(t *testing.T) {
+		dir1, dir2 := t.TempDir(), t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir1, "1_a.sql"), []byte("CREATE TABLE a (id int);"), 0o644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir2, "1_a.sql"), []byte("CREATE TABLE a (id int);"), 0o644))
+
+		s, err := SourceFromDirectory(nil, dir1, WithDirectories(dir2))
+		require.NoError(t, err)
+
+		_, err = s.Load(context.Background())
+		assert.ErrorIs(t, err, migrations.ErrMigrationAlreadyExists)
+		assert.ErrorContains(t, err, filepath.Join(dir1, "1_a.sql")+" and "+filepath.Join(dir2, "1_a.sql"))
+	})
+
+	t.Run("should name the file systems of the same ID declared in different file systems", func(t *testing.T) {
+		files := fstest.MapFS{
+			"migrations/1_a.sql": {Data: []byte("CREATE TABLE a (id int);")},
+		}
+		s, err := SourceFromFS(nil, files, "migrations", WithFS(fstest.MapFS{}, "."), WithFS(files, "migrations"))
+		require.NoError(t, err)
+
+		_, err = s.Load(context.Background())
+		assert.ErrorIs(t, err, migrations.ErrMigrationAlreadyExists)
+		assert.ErrorContains(t, err, "migrations/1_a.sql and migrations/1_a.sql (WithFS #2)")
+	})
+
+	t.Run("should fail when the same ID is declared twice in the same folder", func(t *testing.T) {
+		s, err := SourceFromFS(nil, fstest.MapFS{
+			"1_create_customers.sql": {Data: []byte("CREATE TABLE customers (id int);")},
+			"1_create_orders.sql":    {Data: []byte("CREATE TABLE orders (id int);")},
+		}, ".")
+		require.NoError(t, err)
+
+		_, err = s.Load(context.Background())
+		assert.ErrorIs(t, err, migrations.ErrMigrationAlreadyExists)
+		assert.ErrorContains(t, err, "1_create_customers.sql and 1_create_orders.sql")
+	})
+}
@@ -0,0 +1,218 @@
+package sql
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	statementBeginMarker = "+migrate StatementBegin"
+	statementEndMarker   = "+migrate StatementEnd"
+)
+
+var (
+	ErrUnterminatedStatement   = errors.New("unterminated statement")
+	ErrInvalidStatementMarker  = errors.New("invalid statement marker")
+	errUnterminatedQuote       = errors.New("quote is not closed")
+	errUnterminatedComment     = errors.New("comment is not closed")
+	errUnterminatedDollarQuote = errors.New("dollar quote is not closed")
+)
+
+// statementSplitter splits the content of migration files into the individual statements, so they can be executed one
+// by one by drivers that do not accept multiple statements in a single query.
+//
+// Semicolons inside of quotes ('string', "identifier" and `identifier`), comments (--, /* */, that can be nested as in
+// Postgres, and the MySQL #) and Postgres dollar quotes ($$ and $tag$) do not end a statement. Statements that have
+// semicolons on their own (eg. triggers) can be wrapped by the `-- +migrate StatementBegin` and
+// `-- +migrate StatementEnd` markers.
+type statementSplitter struct {
+	// backslashEscapes makes backslashes escape the next character inside of strings, as MySQL does by default.
+	// Postgres escape strings (E'...') always accept backslash escapes.
+	backslashEscapes bool
+	// hashComments makes `#` start a comment that runs until the end of the line, as in MySQL. It is not the default,
+	// as `#` is an operator in Postgres.
+	hashComments bool
+}
+
+func (s statementSplitter) split(content string) ([]string, error) {
+	var (
+		statements = make([]string, 0)
+		current    strings.Builder
+		hasCode    bool
+		inBlock    bool
+		blockStart int
+	)
+	flush := func() {
+		if hasCode {
+			statements = append(statements, strings.TrimSpace(current.String()))
+		}
+		current.Reset()
+		hasCode = false
+	}
+
+	for i := 0; i < len(content); {
+		c := content[i]
+		switch {
+		case strings.HasPrefix(content[i:], "--"):
+			end := lineEnd(content, i)
+			switch strings.TrimSpace(content[i+2 : end]) {
+			case statementBeginMarker:
+				if inBlock {
+					return nil, fmt.Errorf("%w: StatementBegin at line %d is inside of another statement block", ErrInvalidStatementMarker, lineAt(content, i))
+				}
+				flush()
+				inBlock, blockStart = true, i
+			case statementEndMarker:
+				if !inBlock {
+					return nil, fmt.Errorf("%w: StatementEnd at line %d without a StatementBegin", ErrInvalidStatementMarker, lineAt(content, i))
+				}
+				flush()
+				inBlock = false
+			default:
+				current.WriteString(content[i:end])
+			}
+			i = end
+		case s.hashComments && c == '#':
+			end := lineEnd(content, i)
+			current.WriteString(content[i:end])
+			i = end
+		case strings.HasPrefix(content[i:], "/*"):
+			end := scanBlockComment(content, i)
+			if end < 0 {
+				return nil, fmt.Errorf("%w: %w at line %d", ErrUnterminatedStatement, errUnterminatedComment, lineAt(content, i))
+			}
+			current.WriteString(content[i:end])
+			i = end
+		case c == '\'' || c == '"' || c == '`':
+			escapes := c == '\'' && (s.backslashEscapes || isEscapeString(content, i))
+			end := scanQuoted(content, i, escapes)
+			if end < 0 {
+				return nil, fmt.Errorf("%w: %w at line %d", ErrUnterminatedStatement, errUnterminatedQuote, lineAt(content, i))
+			}
+			current.WriteString(content[i:end])
+			hasCode = true
+			i = end
+		case c == '$':
+			end := i + 1
+			if tag, ok := dollarQuoteTag(content, i); ok {
+				closing := strings.Index(content[i+len(tag):], tag)
+				if closing < 0 {
+					return nil, fmt.Errorf("%w: %w at line %d", ErrUnterminatedStatement, errUnterminatedDollarQuote, lineAt(content, i))
+				}
+				end = i + len(tag) + closing + len(tag)
+			}
+			current.WriteString(content[i:end])
+			hasCode = true
+			i = end
+		case c == ';' && !inBlock:
+			flush()
+			i++
+		default:
+			if !isSpace(c) {
+				hasCode = true
+			}
+			current.WriteByte(c)
+			i++
+		}
+	}
+
+	if inBlock {
+		return nil, fmt.Errorf("%w: StatementBegin at line %d without a StatementEnd", ErrUnterminatedStatement, lineAt(content, blockStart))
+	}
+	flush()
+	return statements, nil
+}
+
+// lineEnd returns the position of the end of the line, or of the content, that has the given position.
+func lineEnd(content string, pos int) int {
+	end := strings.IndexByte(content[pos:], '\n')
+	if end < 0 {
+		return len(content)
+	}
+	return pos + end
+}
+
+// scanBlockComment returns the position right after the comment, started at start, is closed. Comments can be nested,
+// as in Postgres. It returns -1 if the comment is not closed.
+func scanBlockComment(content string, start int) int {
+	depth := 0
+	for i := start; i+1 < len(content); {
+		switch content[i : i+2] {
+		case "/*":
+			depth++
+			i += 2
+		case "*/":
+			depth--
+			i += 2
+			if depth == 0 {
+				return i
+			}
+		default:
+			i++
+		}
+	}
+	return -1
+}
+
+// scanQuoted returns the position right after the quote, started at start, is closed. Quotes are escaped by doubling
+// them and, when escapes is set, by a backslash. It returns -1 if the quote is not closed.
+func scanQuoted(content string, start int, escapes bool) int {
+	quote := content[start]
+	for i := start + 1; i < len(content); i++ {
+		switch content[i] {
+		case '\\':
+			if escapes {
+				i++
+			}
+		case quote:
+			if i+1 < len(content) && content[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// isEscapeString reports whether the quote at the given position starts a Postgres escape string (E'...').
+func isEscapeString(content string, quote int) bool {
+	if quote == 0 || (content[quote-1] != 'E' && content[quote-1] != 'e') {
+		return false
+	}
+	return quote == 1 || !isIdentifierChar(content[quote-2])
+}
+
+// dollarQuoteTag returns the tag ($$ or $tag$) of the Postgres dollar quote started at the given position, if any.
+func dollarQuoteTag(content string, start int) (string, bool) {
+	if start > 0 && isIdentifierChar(content[start-1]) {
+		// Dollar signs can be part of identifiers.
+		return "", false
+	}
+	for i := start + 1; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case c == '$':
+			return content[start : i+1], true
+		case c >= '0' && c <= '9' && i == start+1:
+			// Positional parameters ($1) are not dollar quotes.
+			return "", false
+		case !isIdentifierChar(c):
+			return "", false
+		}
+	}
+	return "", false
+}
+
+func isIdentifierChar(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c >= 0x80
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v'
+}
+
+func lineAt(content string, pos int) int {
+	return strings.Count(content[:pos], "\n") + 1
+}
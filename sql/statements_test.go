@@ -0,0 +1,132 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_statementSplitter_split(t *testing.T) {
+	tests := []struct {
+		name             string
+		backslashEscapes bool
+		hashComments     bool
+		content          string
+		want             []string
+	}{
+		{
+			name:    "single statement without semicolon",
+			content: "CREATE TABLE customers (id int)",
+			want:    []string{"CREATE TABLE customers (id int)"},
+		},
+		{
+			name:    "multiple statements",
+			content: "CREATE TABLE customers (id int);\nINSERT INTO customers VALUES (1);\n",
+			want:    []string{"CREATE TABLE customers (id int)", "INSERT INTO customers VALUES (1)"},
+		},
+		{
+			name:    "semicolons inside of quotes",
+			content: `INSERT INTO t VALUES ('a;b', 'it''s;'); SELECT "a;b", ` + "`c;d`" + ` FROM t;`,
+			want:    []string{`INSERT INTO t VALUES ('a;b', 'it''s;')`, `SELECT "a;b", ` + "`c;d`" + ` FROM t`},
+		},
+		{
+			name:    "semicolons inside of comments",
+			content: "-- first; statement\nSELECT 1; /* second;\nstatement */ SELECT 2;",
+			want:    []string{"-- first; statement\nSELECT 1", "/* second;\nstatement */ SELECT 2"},
+		},
+		{
+			name:    "semicolons inside of nested comments",
+			content: "SELECT 1; /* outer; /* inner; */ still; a comment; */ SELECT 2;",
+			want:    []string{"SELECT 1", "/* outer; /* inner; */ still; a comment; */ SELECT 2"},
+		},
+		{
+			name:         "semicolons inside of hash comments",
+			hashComments: true,
+			content:      "# first; statement\nSELECT 1; # second; statement\nSELECT 2;",
+			want:         []string{"# first; statement\nSELECT 1", "# second; statement\nSELECT 2"},
+		},
+		{
+			name:    "hash operator",
+			content: "SELECT 5 # 3; SELECT 2;",
+			want:    []string{"SELECT 5 # 3", "SELECT 2"},
+		},
+		{
+			name:    "comments only",
+			content: "-- migrations: no-transaction\n; -- nothing to do\n",
+			want:    []string{},
+		},
+		{
+			name: "dollar quoted function",
+			content: `CREATE FUNCTION f() RETURNS trigger AS $$
+BEGIN
+  NEW.updated_at = now();
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+CREATE FUNCTION g() RETURNS text AS $body$ SELECT 'a;b'; $body$ LANGUAGE sql;
+SELECT $1;`,
+			want: []string{
+				"CREATE FUNCTION f() RETURNS trigger AS $$\nBEGIN\n  NEW.updated_at = now();\n  RETURN NEW;\nEND;\n$$ LANGUAGE plpgsql",
+				"CREATE FUNCTION g() RETURNS text AS $body$ SELECT 'a;b'; $body$ LANGUAGE sql",
+				"SELECT $1",
+			},
+		},
+		{
+			name: "statement markers",
+			content: `CREATE TABLE t (id int);
+-- +migrate StatementBegin
+CREATE TRIGGER tr AFTER INSERT ON t
+BEGIN
+  UPDATE t SET id = 1;
+END;
+-- +migrate StatementEnd
+SELECT 1;`,
+			want: []string{
+				"CREATE TABLE t (id int)",
+				"CREATE TRIGGER tr AFTER INSERT ON t\nBEGIN\n  UPDATE t SET id = 1;\nEND;",
+				"SELECT 1",
+			},
+		},
+		{
+			name:    "postgres escape strings",
+			content: `SELECT E'it\'s;'; SELECT 'c:\';`,
+			want:    []string{`SELECT E'it\'s;'`, `SELECT 'c:\'`},
+		},
+		{
+			name:             "backslash escapes",
+			backslashEscapes: true,
+			content:          `SELECT 'it\'s;'; SELECT 2;`,
+			want:             []string{`SELECT 'it\'s;'`, `SELECT 2`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := statementSplitter{backslashEscapes: tt.backslashEscapes, hashComments: tt.hashComments}.split(tt.content)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_statementSplitter_split_errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr error
+	}{
+		{name: "unterminated quote", content: "SELECT 1;\nSELECT 'a;", wantErr: ErrUnterminatedStatement},
+		{name: "unterminated comment", content: "SELECT 1; /* comment", wantErr: ErrUnterminatedStatement},
+		{name: "unterminated nested comment", content: "SELECT 1; /* /* comment */", wantErr: ErrUnterminatedStatement},
+		{name: "unterminated dollar quote", content: "SELECT $$ a;", wantErr: ErrUnterminatedStatement},
+		{name: "missing StatementEnd", content: "-- +migrate StatementBegin\nSELECT 1;", wantErr: ErrUnterminatedStatement},
+		{name: "missing StatementBegin", content: "SELECT 1;\n-- +migrate StatementEnd", wantErr: ErrInvalidStatementMarker},
+		{name: "nested StatementBegin", content: "-- +migrate StatementBegin\n-- +migrate StatementBegin", wantErr: ErrInvalidStatementMarker},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := statementSplitter{}.split(tt.content)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
@@ -22,9 +22,11 @@ type DB interface {
 }
 
 type Target struct {
-	driver    drivers.Driver
-	db        DB
-	tableName string
+	driver drivers.Driver
+	// schema and history are the driver itself, when it implements them, or the generic driver.
+	schema  drivers.SchemaDriver
+	history drivers.HistoryDriver
+	db      DB
 }
 
 func (target *Target) FinishMigration(ctx context.Context, id string) error {
@@ -47,69 +49,69 @@ func NewTarget(db DB, options ...TargetOption) (*Target, error) {
 		}
 	}
 
+	// The table name goes first, so it can still be overridden by the driver options.
+	driverOptions := append([]drivers.Option{drivers.WithTableName(opts.tableName)}, opts.driverOptions...)
 	if opts.driver == nil {
-		d, err := drivers.DriverFromDB(db, opts.driverOptions...)
+		d, err := drivers.DriverFromDB(db, driverOptions...)
 		if err != nil {
 			return nil, err
 		}
 		opts.driver = d
 	}
 
-	return &Target{
-		driver:    opts.driver,
-		db:        db,
-		tableName: opts.tableName,
-	}, nil
+	target := &Target{
+		driver: opts.driver,
+		db:     db,
+	}
+	schema, hasSchema := opts.driver.(drivers.SchemaDriver)
+	history, hasHistory := opts.driver.(drivers.HistoryDriver)
+	if !hasSchema || !hasHistory {
+		// Drivers that only implement drivers.Driver get the generic SQL for the rest.
+		generic, err := drivers.NewGenericDriver(db, driverOptions...)
+		if err != nil {
+			return nil, err
+		}
+		if !hasSchema {
+			schema = generic
+		}
+		if !hasHistory {
+			history = generic
+		}
+	}
+	target.schema, target.history = schema, history
+	return target, nil
 }
 
+// Create creates the migrations table, if it does not exist, using the DDL of the driver. Tables created by older
+// versions are upgraded, adding the missing columns.
 func (target *Target) Create(ctx context.Context) error {
-	_, err := target.db.ExecContext(ctx, fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (id text PRIMARY KEY, dirty bool default true)", target.tableName))
-	return err
+	return target.schema.Create(ctx)
 }
 
+// Destroy drops the migrations table.
 func (target *Target) Destroy(ctx context.Context) error {
-	_, err := target.db.ExecContext(ctx, fmt.Sprintf("DROP TABLE IF EXISTS %s", target.tableName))
-	return err
+	return target.schema.Destroy(ctx)
 }
 
-func (target *Target) Current(ctx context.Context) (string, error) {
-	list, err := target.Done(ctx)
-	if err != nil {
-		return "", err
-	}
-
-	if len(list) == 0 {
-		return "", migrations.ErrNoCurrentMigration
-	}
+// Exists reports whether the migrations table exists, implementing migrations.ExistenceChecker.
+func (target *Target) Exists(ctx context.Context) (bool, error) {
+	return target.schema.Exists(ctx)
+}
 
-	return list[len(list)-1], nil
+// Current returns the ID of the last migration applied, or migrations.ErrNoCurrentMigration if there is none.
+func (target *Target) Current(ctx context.Context) (string, error) {
+	return target.history.Current(ctx)
 }
 
+// Done lists the IDs of the applied migrations, failing with migrations.ErrDirtyMigration if any of them is dirty.
 func (target *Target) Done(ctx context.Context) ([]string, error) {
-	rs, err := target.db.QueryContext(ctx, fmt.Sprintf("SELECT id, dirty FROM %s ORDER BY id ASC", target.tableName))
-	if err != nil {
-		return nil, err
-	}
-	defer func() {
-		_ = rs.Close()
-	}()
-
-	var (
-		id    string
-		dirty bool
-	)
-	result := make([]string, 0)
-	for rs.Next() {
-		err := rs.Scan(&id, &dirty)
-		if err != nil {
-			return nil, err
-		}
-		if dirty {
-			return nil, migrations.WrapMigrationID(migrations.ErrDirtyMigration, id)
-		}
-		result = append(result, id)
-	}
-	return result, nil
+	return target.history.Done(ctx)
+}
+
+// History lists all migrations recorded in the migrations table, including the dirty ones, with the details of their
+// execution.
+func (target *Target) History(ctx context.Context) ([]migrations.HistoryEntry, error) {
+	return target.history.History(ctx)
 }
 
 func (target *Target) Add(ctx context.Context, id string) error {
@@ -120,6 +122,49 @@ func (target *Target) Remove(ctx context.Context, id string) error {
 	return target.driver.Remove(ctx, id)
 }
 
+// Transaction runs fn inside of a database transaction that is carried by the context. Both the SQL migrations and the
+// bookkeeping of the `_migrations` table use that transaction, so they are committed, or rolled back, as a unit.
+//
+// If the context already carries a transaction, fn runs within it.
+func (target *Target) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if TxFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+
+	tx, err := target.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed starting transaction: %w", err)
+	}
+
+	err = fn(drivers.ContextWithTx(ctx, tx))
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("failed committing transaction: %w", err)
+	}
+	return nil
+}
+
+// TxFromContext returns the transaction started by Target.Transaction, or nil if the context does not carry one. Code
+// migrations can use it to run their queries in the same transaction of the bookkeeping.
+func TxFromContext(ctx context.Context) *sql.Tx {
+	return drivers.TxFromContext(ctx)
+}
+
+// RecordMigration marks the migration as finished, storing the details of its execution.
+func (target *Target) RecordMigration(ctx context.Context, entry migrations.HistoryEntry) error {
+	return target.history.RecordMigration(ctx, entry)
+}
+
+// UpdateChecksum replaces the checksum recorded for the migration.
+func (target *Target) UpdateChecksum(ctx context.Context, id string, checksum string) error {
+	return target.history.UpdateChecksum(ctx, id, checksum)
+}
+
 func (target *Target) Lock(ctx context.Context) (migrations.Unlocker, error) {
 	return target.driver.Lock(ctx)
 }
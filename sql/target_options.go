@@ -26,8 +26,14 @@ func WithDriverOptions(options ...drivers.Option) TargetOption {
 	}
 }
 
+// WithTableName sets the name of the migrations table. It can be qualified by its schema (eg. `ops._migrations`), and
+// it is quoted when needed. Names with quotes, or control characters, fail with drivers.ErrInvalidTableName.
 func WithTableName(tableName string) TargetOption {
 	return func(target *targetOpts) error {
+		err := drivers.ValidateTableName(tableName)
+		if err != nil {
+			return err
+		}
 		target.tableName = tableName
 		return nil
 	}
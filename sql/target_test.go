@@ -0,0 +1,135 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jamillosantos/migrations/v2"
+	"github.com/jamillosantos/migrations/v2/sql/drivers"
+)
+
+// basicDriver hides the optional interfaces of the driver, as a third-party driver written against drivers.Driver.
+type basicDriver struct {
+	drivers.Driver
+}
+
+var _ = Describe("Target", func() {
+	var (
+		db     *sql.DB
+		target *Target
+
+		ctx context.Context
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		newDB, err := sql.Open("sqlite3", ":memory:")
+		Expect(err).ToNot(HaveOccurred(), "should open the database")
+		newDB.SetMaxOpenConns(1)
+		db = newDB
+
+		newTarget, err := NewTarget(db, WithDriverOptions(drivers.WithDatabaseName("test")))
+		Expect(err).ToNot(HaveOccurred(), "should create the target")
+		target = newTarget
+	})
+
+	AfterEach(func() {
+		Expect(db.Close()).To(Succeed())
+	})
+
+	It("should upgrade a migrations table created by an older version", func() {
+		_, err := db.ExecContext(ctx, "CREATE TABLE _migrations (id text PRIMARY KEY, dirty bool default true)")
+		Expect(err).ToNot(HaveOccurred())
+		_, err = db.ExecContext(ctx, "INSERT INTO _migrations (id, dirty) VALUES ('1', false)")
+		Expect(err).ToNot(HaveOccurred())
+
+		// The table can be inspected before being upgraded.
+		Expect(target.Done(ctx)).To(Equal([]string{"1"}))
+
+		Expect(target.Create(ctx)).To(Succeed())
+		// Creating it again should not fail, as the table is already upgraded.
+		Expect(target.Create(ctx)).To(Succeed())
+
+		Expect(target.Done(ctx)).To(Equal([]string{"1"}))
+		Expect(target.History(ctx)).To(Equal([]migrations.HistoryEntry{
+			{ID: "1"},
+		}))
+	})
+
+	It("should report whether the migrations table exists", func() {
+		Expect(target.Exists(ctx)).To(BeFalse())
+		Expect(target.Create(ctx)).To(Succeed())
+		Expect(target.Exists(ctx)).To(BeTrue())
+	})
+
+	It("should record the details of the migration execution", func() {
+		Expect(target.Create(ctx)).To(Succeed())
+
+		appliedAt := time.Date(2025, 1, 9, 1, 12, 42, 0, time.UTC)
+		Expect(target.Add(ctx, "1")).To(Succeed())
+		Expect(target.RecordMigration(ctx, migrations.HistoryEntry{
+			ID:            "1",
+			Description:   "create people table",
+			Checksum:      "checksum",
+			AppliedAt:     appliedAt,
+			ExecutionTime: 1500 * time.Millisecond,
+		})).To(Succeed())
+
+		history, err := target.History(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(history).To(HaveLen(1))
+		Expect(history[0].ID).To(Equal("1"))
+		Expect(history[0].Dirty).To(BeFalse())
+		Expect(history[0].Description).To(Equal("create people table"))
+		Expect(history[0].Checksum).To(Equal("checksum"))
+		Expect(history[0].AppliedAt.Equal(appliedAt)).To(BeTrue())
+		Expect(history[0].ExecutionTime).To(Equal(1500 * time.Millisecond))
+
+		Expect(target.Current(ctx)).To(Equal("1"))
+	})
+	When("the table name is schema-qualified and needs quoting", func() {
+		It("should create and use the table", func() {
+			newTarget, err := NewTarget(db, WithTableName("main.my migrations"))
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(newTarget.Create(ctx)).To(Succeed())
+			Expect(newTarget.Add(ctx, "1")).To(Succeed())
+			Expect(newTarget.FinishMigration(ctx, "1")).To(Succeed())
+			Expect(newTarget.Done(ctx)).To(Equal([]string{"1"}))
+
+			rows, err := db.QueryContext(ctx, `SELECT id FROM main."my migrations"`)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rows.Next()).To(BeTrue())
+			Expect(rows.Close()).To(Succeed())
+
+			Expect(newTarget.Destroy(ctx)).To(Succeed())
+		})
+	})
+
+	When("the driver only implements drivers.Driver", func() {
+		It("should manage the table with the generic SQL", func() {
+			driver, err := drivers.DriverFromDB(db, drivers.WithDatabaseName("test"))
+			Expect(err).ToNot(HaveOccurred())
+
+			newTarget, err := NewTarget(db, WithDriver(basicDriver{driver}))
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(newTarget.Create(ctx)).To(Succeed())
+			Expect(newTarget.Add(ctx, "1")).To(Succeed())
+			Expect(newTarget.FinishMigration(ctx, "1")).To(Succeed())
+			Expect(newTarget.Done(ctx)).To(Equal([]string{"1"}))
+			Expect(newTarget.Current(ctx)).To(Equal("1"))
+		})
+	})
+
+	It("should reject invalid table names", func() {
+		_, err := NewTarget(db, WithTableName("_migrations; DROP TABLE users"))
+		Expect(err).To(MatchError(drivers.ErrInvalidTableName))
+	})
+})
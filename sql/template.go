@@ -0,0 +1,39 @@
+package sql
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+const templateExtension = ".tmpl"
+
+var ErrTemplate = errors.New("failed rendering migration template")
+
+// migrationTemplate renders the migration files through text/template.
+type migrationTemplate struct {
+	data  map[string]any
+	funcs template.FuncMap
+}
+
+// render renders the content of the file. Keys missing in the data fail the rendering, so a typo does not end up as an
+// empty value in the SQL.
+func (t *migrationTemplate) render(file, content string) (string, error) {
+	tmpl, err := template.New(file).Funcs(t.funcs).Option("missingkey=error").Parse(content)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrTemplate, err)
+	}
+
+	var buf strings.Builder
+	err = tmpl.Execute(&buf, t.data)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrTemplate, err)
+	}
+	return buf.String(), nil
+}
+
+// isTemplateFile reports whether the file is a template (`.sql.tmpl`), which is always rendered.
+func isTemplateFile(file string) bool {
+	return strings.HasSuffix(file, templateExtension)
+}
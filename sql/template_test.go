@@ -0,0 +1,71 @@
+package sql
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jamillosantos/migrations/v2"
+)
+
+func TestSource_Template(t *testing.T) {
+	files := fstest.MapFS{
+		"1_create_customers.sql":      {Data: []byte("CREATE TABLE {{ .schema }}.customers (id int);")},
+		"2_grant_customers.sql.tmpl":  {Data: []byte("GRANT SELECT ON {{ .schema }}.customers TO {{ upper .role }};")},
+		"3_create_orders.up.sql.tmpl": {Data: []byte("CREATE TABLE {{ .schema }}.orders (id int);")},
+		"3_create_orders.down.sql":    {Data: []byte("DROP TABLE {{ .schema }}.orders;")},
+	}
+	funcs := template.FuncMap{"upper": strings.ToUpper}
+
+	t.Run("should render all files", func(t *testing.T) {
+		ctx := context.Background()
+		s, err := SourceFromFS(nil, files, ".", WithTemplate(map[string]any{"schema": "ops", "role": "reporting"}, funcs))
+		require.NoError(t, err)
+
+		repo, err := s.Load(ctx)
+		require.NoError(t, err)
+		list, err := repo.List(ctx)
+		require.NoError(t, err)
+		require.Len(t, list, 3)
+
+		m1 := list[0].(*migrationSQL)
+		assert.Equal(t, "CREATE TABLE ops.customers (id int);", m1.Script(migrations.ActionTypeDo))
+		assert.Equal(t, (&migrationSQL{doFileContent: "CREATE TABLE ops.customers (id int);"}).Checksum(), m1.Checksum())
+		assert.Equal(t, "GRANT SELECT ON ops.customers TO REPORTING;", list[1].(*migrationSQL).Script(migrations.ActionTypeDo))
+		assert.Equal(t, "DROP TABLE ops.orders;", list[2].(*migrationSQL).Script(migrations.ActionTypeUndo))
+	})
+
+	t.Run("should render only the template files without the option", func(t *testing.T) {
+		ctx := context.Background()
+		s, err := SourceFromFS(nil, fstest.MapFS{
+			"1_create_customers.sql":     files["1_create_customers.sql"],
+			"2_create_orders.sql.tmpl":   {Data: []byte("CREATE TABLE orders (id int{{ if true }}, name text{{ end }});")},
+			"3_create_products.sql.tmpl": {Data: []byte("-- +up\nCREATE TABLE products (id int);\n-- +down\nDROP TABLE products;")},
+		}, ".")
+		require.NoError(t, err)
+
+		repo, err := s.Load(ctx)
+		require.NoError(t, err)
+		list, err := repo.List(ctx)
+		require.NoError(t, err)
+		require.Len(t, list, 3)
+
+		assert.Equal(t, "CREATE TABLE {{ .schema }}.customers (id int);", list[0].(*migrationSQL).Script(migrations.ActionTypeDo))
+		assert.Equal(t, "CREATE TABLE orders (id int, name text);", list[1].(*migrationSQL).Script(migrations.ActionTypeDo))
+		assert.True(t, list[2].CanUndo())
+	})
+
+	t.Run("should fail when the data is missing a key", func(t *testing.T) {
+		s, err := SourceFromFS(nil, files, ".", WithTemplate(map[string]any{"schema": "ops"}, funcs))
+		require.NoError(t, err)
+
+		_, err = s.Load(context.Background())
+		assert.ErrorIs(t, err, ErrTemplate)
+		assert.ErrorContains(t, err, "2_grant_customers.sql.tmpl")
+	})
+}
@@ -0,0 +1,5 @@
+CREATE TABLE customers
+(
+    id   SERIAL PRIMARY KEY,
+    name VARCHAR(150) NOT NULL
+)
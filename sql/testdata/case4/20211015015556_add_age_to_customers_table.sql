@@ -0,0 +1 @@
+ALTER TABLE customers ADD COLUMN age int;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE customers ADD COLUMN nickname VARCHAR(50);
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE customers ADD COLUMN birthday date;
\ No newline at end of file
@@ -0,0 +1,171 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	_ "github.com/mattn/go-sqlite3"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jamillosantos/migrations/v2"
+	"github.com/jamillosantos/migrations/v2/fnc"
+	"github.com/jamillosantos/migrations/v2/sql/drivers"
+)
+
+var _ = Describe("Transactions", func() {
+	var (
+		db     *sql.DB
+		target *Target
+
+		ctx context.Context
+	)
+
+	newMigration := func(id, content string) *migrationSQL {
+		directives, err := parseFileDirectives(content)
+		Expect(err).ToNot(HaveOccurred())
+		return &migrationSQL{
+			dbGetter: func() DBExecer {
+				return db
+			},
+			id:            id,
+			doFile:        id + "_migration.sql",
+			doFileContent: content,
+			noTransaction: directives.noTransaction,
+		}
+	}
+
+	tableExists := func(name string) bool {
+		var count int
+		Expect(db.QueryRowContext(ctx, "SELECT count(*) FROM sqlite_master WHERE type = 'table' AND name = $1", name).Scan(&count)).To(Succeed())
+		return count > 0
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		newDB, err := sql.Open("sqlite3", ":memory:")
+		Expect(err).ToNot(HaveOccurred(), "should open the database")
+		// Keeps a single connection, otherwise each new connection would open a new in-memory database.
+		newDB.SetMaxOpenConns(1)
+		db = newDB
+
+		newTarget, err := NewTarget(db, WithDriverOptions(drivers.WithDatabaseName("test")))
+		Expect(err).ToNot(HaveOccurred(), "should create the target")
+		target = newTarget
+
+		Expect(target.Create(ctx)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(db.Close()).To(Succeed())
+	})
+
+	When("running each migration in its own transaction", func() {
+		It("should apply the migration and its bookkeeping", func() {
+			runner := migrations.NewRunner(nil, target, migrations.WithTransactionMode(migrations.TransactionModeMigration))
+
+			_, err := runner.Execute(ctx, &migrations.ExecuteRequest{
+				Plan: migrations.Plan{
+					{Action: migrations.ActionTypeDo, Migration: newMigration("1", "CREATE TABLE customers (id int)")},
+				},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(tableExists("customers")).To(BeTrue())
+			Expect(target.Done(ctx)).To(Equal([]string{"1"}))
+		})
+
+		It("should roll back the migration and its bookkeeping when it fails", func() {
+			runner := migrations.NewRunner(nil, target, migrations.WithTransactionMode(migrations.TransactionModeMigration))
+
+			_, err := runner.Execute(ctx, &migrations.ExecuteRequest{
+				Plan: migrations.Plan{
+					{Action: migrations.ActionTypeDo, Migration: newMigration("1", "CREATE TABLE customers (id int)")},
+					{Action: migrations.ActionTypeDo, Migration: newMigration("2", "CREATE TABLE orders (id int); INVALID SQL")},
+				},
+			})
+			Expect(err).To(HaveOccurred())
+
+			Expect(tableExists("customers")).To(BeTrue())
+			Expect(tableExists("orders")).To(BeFalse())
+			Expect(target.Done(ctx)).To(Equal([]string{"1"}))
+		})
+	})
+
+	When("running the whole plan in a single transaction", func() {
+		It("should roll back all migrations when any of them fails", func() {
+			runner := migrations.NewRunner(nil, target, migrations.WithTransactionMode(migrations.TransactionModePlan))
+
+			stats, err := runner.Execute(ctx, &migrations.ExecuteRequest{
+				Plan: migrations.Plan{
+					{Action: migrations.ActionTypeDo, Migration: newMigration("1", "CREATE TABLE customers (id int)")},
+					{Action: migrations.ActionTypeDo, Migration: newMigration("2", "INVALID SQL")},
+				},
+			})
+			Expect(err).To(HaveOccurred())
+			Expect(stats.Successful).To(BeEmpty())
+
+			Expect(tableExists("customers")).To(BeFalse())
+			Expect(target.Done(ctx)).To(BeEmpty())
+		})
+
+		It("should refuse a plan with a migration that cannot run in a transaction", func() {
+			runner := migrations.NewRunner(nil, target, migrations.WithTransactionMode(migrations.TransactionModePlan))
+
+			_, err := runner.Execute(ctx, &migrations.ExecuteRequest{
+				Plan: migrations.Plan{
+					{Action: migrations.ActionTypeDo, Migration: newMigration("1", "CREATE TABLE customers (id int)")},
+					{Action: migrations.ActionTypeDo, Migration: newMigration("2", "-- migrations: no-transaction\nCREATE INDEX idx ON customers (id)")},
+				},
+			})
+			Expect(err).To(MatchError(migrations.ErrNonTransactionalMigration))
+
+			Expect(tableExists("customers")).To(BeFalse())
+		})
+	})
+
+	When("running a fnc.TxMigration", func() {
+		BeforeEach(func() {
+			_, err := db.ExecContext(ctx, "CREATE TABLE customers (name TEXT)")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		countCustomers := func() int {
+			var count int
+			Expect(db.QueryRowContext(ctx, "SELECT count(*) FROM customers").Scan(&count)).To(Succeed())
+			return count
+		}
+
+		It("should run the migration and its bookkeeping in the same transaction", func() {
+			registry := fnc.NewRegistry()
+			fnc.TxMigration(func(ctx context.Context, tx *sql.Tx) error {
+				_, err := tx.ExecContext(ctx, "INSERT INTO customers (name) VALUES ('John')")
+				return err
+			}, fnc.WithRegistry(registry))
+
+			_, err := migrations.Migrate(ctx, registry.Source(), target)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(countCustomers()).To(Equal(1))
+			Expect(target.Done(ctx)).To(Equal([]string{"transaction"}))
+		})
+
+		It("should roll back the migration and its bookkeeping when it fails", func() {
+			wantErr := errors.New("random error")
+			registry := fnc.NewRegistry()
+			fnc.TxMigration(func(ctx context.Context, tx *sql.Tx) error {
+				_, err := tx.ExecContext(ctx, "INSERT INTO customers (name) VALUES ('John')")
+				Expect(err).ToNot(HaveOccurred())
+				return wantErr
+			}, fnc.WithRegistry(registry))
+
+			_, err := migrations.Migrate(ctx, registry.Source(), target)
+			Expect(err).To(MatchError(wantErr))
+
+			Expect(countCustomers()).To(Equal(0))
+			Expect(target.Done(ctx)).To(BeEmpty())
+		})
+	})
+})
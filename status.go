@@ -0,0 +1,172 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// MigrationState is the state of a migration, considering both the Source and the Target.
+type MigrationState string
+
+const (
+	// MigrationStateApplied is a migration that was successfully applied.
+	MigrationStateApplied MigrationState = "applied"
+	// MigrationStatePending is a migration that was not applied yet.
+	MigrationStatePending MigrationState = "pending"
+	// MigrationStateMissing is a migration that was applied, but cannot be found in the Source.
+	MigrationStateMissing MigrationState = "missing"
+	// MigrationStateDirty is a migration that was started but not completed.
+	MigrationStateDirty MigrationState = "dirty"
+	// MigrationStateOutOfOrder is a migration that was not applied yet, but it is older than the current migration.
+	MigrationStateOutOfOrder MigrationState = "out-of-order"
+)
+
+// MigrationStatus is the report of a single migration returned by Status.
+type MigrationStatus struct {
+	ID          string         `json:"id"`
+	Description string         `json:"description,omitempty"`
+	State       MigrationState `json:"state"`
+	// Migration is the migration from the Source. It is nil when the State is MigrationStateMissing.
+	Migration Migration `json:"-"`
+}
+
+// HistoryEntry is a migration recorded by a Target, with the details of its execution. Details are zero valued when the
+// Target does not record them, or when the migration was recorded by an older version of it.
+type HistoryEntry struct {
+	ID          string
+	Dirty       bool
+	Description string
+	// Checksum is the checksum of the migration content when it was applied. See ChecksummedMigration.
+	Checksum string
+	// AppliedAt is the moment the migration was successfully applied.
+	AppliedAt time.Time
+	// ExecutionTime is how long the migration took to be applied.
+	ExecutionTime time.Duration
+}
+
+// MigrationRecorder is an optional interface for Targets that store the details of the migrations applied. When
+// implemented, the Runner calls RecordMigration instead of Target.FinishMigration after applying a migration.
+type MigrationRecorder interface {
+	// RecordMigration marks the migration as finished, storing the details of its execution.
+	RecordMigration(ctx context.Context, entry HistoryEntry) error
+}
+
+// ChecksummedMigration is an optional interface for migrations that can provide a checksum of their content. The
+// checksum is recorded by Targets implementing MigrationRecorder.
+type ChecksummedMigration interface {
+	Checksum() string
+}
+
+// HistoryTarget is an optional interface for Targets that can list every migration recorded, including the dirty ones
+// that make Target.Done fail.
+type HistoryTarget interface {
+	// History lists all migrations recorded by the Target, sorted by ID.
+	History(ctx context.Context) ([]HistoryEntry, error)
+}
+
+// Status joins the migrations available in the source with the ones recorded by the target, returning a report for
+// each of them sorted by ID.
+//
+// Dirty migrations are only reported if the target implements HistoryTarget. Otherwise, the error from Target.Done is
+// returned.
+func Status(ctx context.Context, source Source, target Target) ([]MigrationStatus, error) {
+	repo, err := source.Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error listing available migrations", err)
+	}
+
+	migrationList, err := repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := targetHistory(ctx, target)
+	if err != nil {
+		return nil, fmt.Errorf("failed listing migrations applied: %w", err)
+	}
+
+	var currentMigrationID string
+	recorded := make(map[string]HistoryEntry, len(history))
+	for _, entry := range history {
+		recorded[entry.ID] = entry
+		if entry.ID > currentMigrationID {
+			currentMigrationID = entry.ID
+		}
+	}
+
+	result := make([]MigrationStatus, 0, len(migrationList)+len(history))
+	for _, m := range migrationList {
+		status := MigrationStatus{
+			ID:          m.ID(),
+			Description: m.Description(),
+			Migration:   m,
+		}
+		entry, ok := recorded[m.ID()]
+		switch {
+		case ok && entry.Dirty:
+			status.State = MigrationStateDirty
+		case ok:
+			status.State = MigrationStateApplied
+		case m.ID() < currentMigrationID:
+			status.State = MigrationStateOutOfOrder
+		default:
+			status.State = MigrationStatePending
+		}
+		result = append(result, status)
+	}
+
+	for _, entry := range history {
+		_, err := repo.ByID(entry.ID)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, ErrMigrationNotFound) {
+			return nil, err
+		}
+		status := MigrationStatus{
+			ID:    entry.ID,
+			State: MigrationStateMissing,
+		}
+		if entry.Dirty {
+			status.State = MigrationStateDirty
+		}
+		result = append(result, status)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+
+	return result, nil
+}
+
+// targetHistory lists the migrations recorded by the target. If it does not implement HistoryTarget, the history is
+// built from Target.Done. A target that reports, through ExistenceChecker, it was not created yet has no history.
+func targetHistory(ctx context.Context, target Target) ([]HistoryEntry, error) {
+	if checker, ok := target.(ExistenceChecker); ok {
+		exists, err := checker.Exists(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return []HistoryEntry{}, nil
+		}
+	}
+
+	if historyTarget, ok := target.(HistoryTarget); ok {
+		return historyTarget.History(ctx)
+	}
+
+	done, err := target.Done(ctx)
+	if err != nil {
+		return nil, err
+	}
+	history := make([]HistoryEntry, len(done))
+	for i, id := range done {
+		history[i] = HistoryEntry{ID: id}
+	}
+	return history, nil
+}
@@ -0,0 +1,128 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// historyTarget decorates the MockTarget with the HistoryTarget implementation.
+type historyTarget struct {
+	*MockTarget
+	history []HistoryEntry
+}
+
+func (t *historyTarget) History(_ context.Context) ([]HistoryEntry, error) {
+	return t.history, nil
+}
+
+func newMockMigrationWithDescription(ctrl *gomock.Controller, id string) *MockMigration {
+	m := newMockMigration(ctrl, id)
+	m.EXPECT().Description().Return("description " + id).AnyTimes()
+	return m
+}
+
+func TestStatus(t *testing.T) {
+	t.Run("should report the state of every migration", func(t *testing.T) {
+		ctx := context.Background()
+		ctrl := gomock.NewController(t)
+
+		m1 := newMockMigrationWithDescription(ctrl, "1") // applied
+		m2 := newMockMigrationWithDescription(ctrl, "2") // out of order
+		m4 := newMockMigrationWithDescription(ctrl, "4") // dirty
+		m5 := newMockMigrationWithDescription(ctrl, "5") // pending
+
+		source := NewMockSource(ctrl)
+		source.EXPECT().
+			Load(ctx).
+			Return(RepositoryBuilder().WithMigration(m1, m2, m4, m5).Build(), nil)
+
+		target := &historyTarget{
+			MockTarget: NewMockTarget(ctrl),
+			history: []HistoryEntry{
+				{ID: "1"},
+				{ID: "3"}, // missing from the source
+				{ID: "4", Dirty: true},
+			},
+		}
+
+		got, err := Status(ctx, source, target)
+		require.NoError(t, err)
+		assert.Equal(t, []MigrationStatus{
+			{ID: "1", Description: "description 1", State: MigrationStateApplied, Migration: m1},
+			{ID: "2", Description: "description 2", State: MigrationStateOutOfOrder, Migration: m2},
+			{ID: "3", State: MigrationStateMissing},
+			{ID: "4", Description: "description 4", State: MigrationStateDirty, Migration: m4},
+			{ID: "5", Description: "description 5", State: MigrationStatePending, Migration: m5},
+		}, got)
+	})
+
+	t.Run("should build the report from Done when the target has no history", func(t *testing.T) {
+		ctx := context.Background()
+		ctrl := gomock.NewController(t)
+
+		m1 := newMockMigrationWithDescription(ctrl, "1")
+		m2 := newMockMigrationWithDescription(ctrl, "2")
+
+		source := NewMockSource(ctrl)
+		source.EXPECT().
+			Load(ctx).
+			Return(RepositoryBuilder().WithMigration(m1, m2).Build(), nil)
+
+		target := NewMockTarget(ctrl)
+		target.EXPECT().
+			Done(ctx).
+			Return([]string{m1.ID()}, nil)
+
+		got, err := Status(ctx, source, target)
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+		assert.Equal(t, MigrationStateApplied, got[0].State)
+		assert.Equal(t, MigrationStatePending, got[1].State)
+	})
+
+	t.Run("should report every migration as pending when the target was not created", func(t *testing.T) {
+		ctx := context.Background()
+		ctrl := gomock.NewController(t)
+
+		m1 := newMockMigrationWithDescription(ctrl, "1")
+
+		source := NewMockSource(ctrl)
+		source.EXPECT().
+			Load(ctx).
+			Return(RepositoryBuilder().WithMigration(m1).Build(), nil)
+
+		// Listing the migrations of the target would fail the test, as none is expected.
+		target := &existenceTarget{MockTarget: NewMockTarget(ctrl)}
+
+		got, err := Status(ctx, source, target)
+		require.NoError(t, err)
+		assert.Equal(t, []MigrationStatus{
+			{ID: "1", Description: "description 1", State: MigrationStatePending, Migration: m1},
+		}, got)
+	})
+
+	t.Run("should fail when listing the applied migrations fails", func(t *testing.T) {
+		ctx := context.Background()
+		ctrl := gomock.NewController(t)
+
+		wantErr := errors.New("random error")
+
+		source := NewMockSource(ctrl)
+		source.EXPECT().
+			Load(ctx).
+			Return(Repository{}, nil)
+
+		target := NewMockTarget(ctrl)
+		target.EXPECT().
+			Done(ctx).
+			Return(nil, wantErr)
+
+		_, err := Status(ctx, source, target)
+		require.ErrorIs(t, err, wantErr)
+	})
+}